@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func checkCmd() *command {
+	return &command{
+		Name: "check",
+		Description: "fail when generated files differ from code generated by their invocations (path is set) " +
+			"or by jobs of config",
+		Args:   []*cli.ArgDefinition{pathArg(false)},
+		Opts:   generateOpts(),
+		Action: check,
+	}
+}
+
+func check(ctx *cli.Context) error {
+	action := func(command *cmd.Command, params *cmd.Params) error {
+		params.Check = true
+
+		return command.Run(ctx.Context, params)
+	}
+
+	if ctx.GetArg("path") != "" {
+		return runGenerated(ctx, action)
+	}
+
+	return runJobs(ctx, action)
+}
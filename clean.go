@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func cleanCmd() *command {
+	return &command{
+		Name: "clean",
+		Description: fmt.Sprintf(
+			"remove stale generated files recorded in %s, files modified by hand are kept",
+			cmd.ManifestFilename,
+		),
+		Opts: []*cli.OptDefinition{
+			{
+				Name:        "all",
+				Description: "remove all recorded files, not only stale ones",
+			},
+			{
+				Name:        "dry-run",
+				Description: "print files which would be removed, without removing",
+			},
+		},
+		Action: clean,
+	}
+}
+
+func clean(ctx *cli.Context) error {
+	manifest, err := loadManifest()
+	if err != nil {
+		return err
+	}
+
+	removed := make([]*cmd.ManifestFile, 0)
+
+	for _, file := range manifest.Files {
+		if !file.Stale && !hasOpt(ctx.Opts, "all") {
+			continue
+		}
+
+		status, statusErr := manifest.Status(file)
+		if statusErr != nil {
+			return statusErr
+		}
+
+		switch status {
+		case cmd.FileStatusMissing:
+			removed = append(removed, file)
+
+			continue
+		case cmd.FileStatusModified:
+			slog.WarnContext(ctx.Context, "[main] file modified by hand, keeping it", slog.String("file", file.Path))
+
+			continue
+		}
+
+		if hasOpt(ctx.Opts, "dry-run") {
+			_, err = fmt.Fprintf(stdout, "remove %s\n", file.Path)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err = os.Remove(manifest.AbsPath(file))
+		if err != nil {
+			return fmt.Errorf("failed to remove %q: %w", file.Path, err)
+		}
+
+		removed = append(removed, file)
+
+		slog.InfoContext(ctx.Context, "[main] file removed", slog.String("file", file.Path))
+	}
+
+	if hasOpt(ctx.Opts, "dry-run") || len(removed) == 0 {
+		return nil
+	}
+
+	manifest.Remove(removed)
+
+	return manifest.Save()
+}
+
+// loadManifest loads manifest of module in working directory.
+func loadManifest() (*cmd.Manifest, error) {
+	goMod, err := findCurrentGoModule()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find current go.mod file: %w", err)
+	}
+
+	return cmd.LoadManifest(filepath.Dir(goMod.Path))
+}
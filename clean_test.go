@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+// newTestModule creates module with generated files recorded in manifest and changes working directory to it.
+func newTestModule(t *testing.T, files map[string]*cmd.ManifestFile, contents map[string]string) *cmd.Manifest {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/m\n\ngo 1.22\n")
+
+	manifest := &cmd.Manifest{Dir: dir}
+
+	for path, file := range files {
+		file.Path = path
+		if file.Hash == "" {
+			sum := sha256.Sum256([]byte(contents[path]))
+			file.Hash = "sha256:" + hex.EncodeToString(sum[:])
+		}
+
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	for path, content := range contents {
+		writeTestFile(t, filepath.Join(dir, path), content)
+	}
+
+	err := manifest.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chdir(t, dir)
+
+	return manifest
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+}
+
+// captureStdout replaces output of commands with buffer.
+func captureStdout(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	prev := stdout
+	stdout = out
+
+	t.Cleanup(func() {
+		stdout = prev
+	})
+
+	return out
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestContext(opts map[string]string) *cli.Context {
+	return &cli.Context{
+		Context: context.Background(),
+		Args:    map[string]string{},
+		Opts:    opts,
+	}
+}
+
+func TestClean(t *testing.T) {
+	newTestModule(t, map[string]*cmd.ManifestFile{
+		"out/stale.go":    {Stale: true},
+		"out/modified.go": {Stale: true, Hash: "sha256:old"},
+		"out/current.go":  {},
+	}, map[string]string{
+		"out/stale.go":    "package out\n",
+		"out/modified.go": "package out\n",
+		"out/current.go":  "package out\n",
+	})
+
+	out := captureStdout(t)
+
+	err := clean(newTestContext(map[string]string{"dry-run": ""}))
+	if err != nil {
+		t.Fatalf("clean() with --dry-run error = %v", err)
+	}
+
+	if out.String() != "remove out/stale.go\n" {
+		t.Errorf("clean() with --dry-run printed %q, want %q", out.String(), "remove out/stale.go\n")
+	}
+
+	if _, statErr := os.Stat(filepath.Join("out", "stale.go")); statErr != nil {
+		t.Errorf("clean() with --dry-run removed file: %v", statErr)
+	}
+
+	err = clean(newTestContext(map[string]string{}))
+	if err != nil {
+		t.Fatalf("clean() error = %v", err)
+	}
+
+	for path, exists := range map[string]bool{
+		"out/stale.go":    false,
+		"out/modified.go": true,
+		"out/current.go":  true,
+	} {
+		_, statErr := os.Stat(filepath.FromSlash(path))
+		if exists != (statErr == nil) {
+			t.Errorf("file %s exists = %v, want %v", path, statErr == nil, exists)
+		}
+	}
+
+	manifest, err := loadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest.Files) != 2 {
+		t.Errorf("manifest must keep 2 files, got %d", len(manifest.Files))
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/artarts36/gostub/internal/cmd"
+	"github.com/artarts36/gostub/internal/config"
+	cli "github.com/artarts36/singlecli"
+)
+
+const (
+	optConfig = "config"
+	optJob    = "job"
+)
+
+// stdout is output of commands for reports, logs are written by slog.
+var stdout io.Writer = os.Stdout
+
+// command is subcommand of gostub: "gostub <name> [args] [options]".
+type command struct {
+	Name        string
+	Description string
+	Args        []*cli.ArgDefinition
+	Opts        []*cli.OptDefinition
+	Action      cli.Action
+}
+
+func commands() []*command {
+	return []*command{
+		generateCmd(),
+		listCmd(),
+		checkCmd(),
+		initCmd(),
+		cleanCmd(),
+		statusCmd(),
+		regenCmd(),
+		syncCmd(),
+		watchCmd(),
+	}
+}
+
+func findCommand(name string) *command {
+	for _, c := range commands() {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// app creates application of command with shared options.
+func (c *command) app() *cli.App {
+	return &cli.App{
+		BuildInfo: &cli.BuildInfo{
+			Name:        "gostub " + c.Name,
+			Description: c.Description,
+			Version:     Version,
+		},
+		Args:   c.Args,
+		Opts:   append(sharedOpts(), c.Opts...),
+		Action: c.Action,
+	}
+}
+
+// args returns arguments for application of command.
+func (c *command) args(osArgs []string) []string {
+	args := osArgs[1:]
+
+	// singlecli prints help for application with arguments called without them,
+	// so empty value is passed for commands which arguments are optional
+	if len(args) == 1 && len(c.Args) > 0 && !c.hasRequiredArgs() {
+		args = append(args, "")
+	}
+
+	return args
+}
+
+func (c *command) hasRequiredArgs() bool {
+	for _, arg := range c.Args {
+		if arg.Required {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sharedOpts() []*cli.OptDefinition {
+	return []*cli.OptDefinition{
+		{
+			Name:        optConfig,
+			Description: fmt.Sprintf("path to config file, %s by default", config.DefaultFilename),
+			WithValue:   true,
+		},
+		{
+			Name:        optJob,
+			Description: "run only job of config with given name",
+			WithValue:   true,
+		},
+	}
+}
+
+func sourceArg() *cli.ArgDefinition {
+	return &cli.ArgDefinition{
+		Name:        "source",
+		Description: "path to source .go file, jobs of config are used when it is not set",
+	}
+}
+
+func pathArg(required bool) *cli.ArgDefinition {
+	return &cli.ArgDefinition{
+		Name:        "path",
+		Required:    required,
+		Description: "path to scan: ./..., ./dir/..., ./dir or ./file.go",
+	}
+}
+
+// job is generation of source with options, paths are relative to Dir.
+type job struct {
+	Title  string
+	Dir    string
+	Source string
+	Opts   map[string]string
+}
+
+// absPath returns path of job in file system, relative paths are resolved from directory of job.
+func (j *job) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(j.Dir, path)
+}
+
+func configPath(ctx *cli.Context) string {
+	if path := ctx.Opts[optConfig]; path != "" {
+		return path
+	}
+
+	return config.DefaultFilename
+}
+
+// loadJobs returns job of source argument or jobs of config, options of command line override options of jobs.
+func loadJobs(ctx *cli.Context) ([]*job, error) {
+	overrides := map[string]string{}
+	for name, value := range ctx.Opts {
+		if name != optConfig && name != optJob {
+			overrides[name] = value
+		}
+	}
+
+	if source := ctx.GetArg("source"); source != "" {
+		return []*job{
+			{
+				Title:  source,
+				Dir:    ".",
+				Source: source,
+				Opts:   overrides,
+			},
+		}, nil
+	}
+
+	path := configPath(ctx)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// jobs run in directory of config, paths of command line are relative to working directory
+	overrides, err = cmd.AbsPathOpts(overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*job, 0, len(cfg.Jobs))
+
+	for _, cfgJob := range cfg.Jobs {
+		if name, ok := ctx.Opts[optJob]; ok && cfgJob.Name != name {
+			continue
+		}
+
+		opts := cfgJob.Opts()
+		for name, value := range overrides {
+			opts[name] = value
+		}
+
+		jobs = append(jobs, &job{
+			Title:  cfgJob.Title(),
+			Dir:    filepath.Dir(path),
+			Source: cfgJob.Source,
+			Opts:   opts,
+		})
+	}
+
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("jobs not found in %s", path)
+	}
+
+	return jobs, nil
+}
+
+// runJobs prepares command for every job of source argument or config and runs action in directory of job.
+func runJobs(ctx *cli.Context, action func(command *cmd.Command, params *cmd.Params) error) error {
+	jobs, err := loadJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runJobList(ctx, jobs, action)
+}
+
+func runJobList(ctx *cli.Context, jobs []*job, action func(command *cmd.Command, params *cmd.Params) error) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var errs []error
+
+	for _, j := range jobs {
+		slog.InfoContext(ctx.Context, "[main] running job", slog.String("job", j.Title))
+
+		err = runJob(ctx, j, action)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", j.Title, err))
+		}
+
+		err = os.Chdir(wd)
+		if err != nil {
+			return fmt.Errorf("failed to return to working directory: %w", err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func runJob(ctx *cli.Context, j *job, action func(command *cmd.Command, params *cmd.Params) error) error {
+	err := os.Chdir(j.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to change directory to %q: %w", j.Dir, err)
+	}
+
+	command, params, err := prepare(ctx.Context, j.Source, j.Opts)
+	if err != nil {
+		return err
+	}
+
+	return action(command, params)
+}
+
+// usageExamples lists commands in help of root application.
+func usageExamples() []*cli.UsageExample {
+	examples := make([]*cli.UsageExample, 0, len(commands()))
+
+	for _, c := range commands() {
+		examples = append(examples, &cli.UsageExample{
+			Command:     "gostub " + c.Name,
+			Description: c.Description,
+		})
+	}
+
+	return examples
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadJobsResolvesOverridePathsFromWorkingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	writeTestFile(t, filepath.Join("api", "gostub.yaml"), `jobs:
+  - source: ./contracts.go
+    out: ./stubs
+    templates: ./templates
+`)
+
+	jobs, err := loadJobs(newTestContext(map[string]string{
+		optConfig:     filepath.Join("api", "gostub.yaml"),
+		"out":         "gen",
+		"method-body": "file:body.tpl",
+		"update":      "",
+	}))
+	if err != nil {
+		t.Fatalf("loadJobs() error = %v", err)
+	}
+
+	if len(jobs) != 1 {
+		t.Fatalf("loadJobs() returned %d jobs, want 1", len(jobs))
+	}
+
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &job{
+		Title:  "./contracts.go",
+		Dir:    "api",
+		Source: "./contracts.go",
+		Opts: map[string]string{
+			"out":         filepath.Join(wd, "gen"),
+			"templates":   "./templates",
+			"method-body": "file:" + filepath.Join(wd, "body.tpl"),
+			"update":      "",
+		},
+	}
+
+	if !reflect.DeepEqual(jobs[0], want) {
+		t.Errorf("loadJobs() = %+v, want %+v", jobs[0], want)
+	}
+}
+
+func TestLoadJobsDisablesFlagsOfConfigByFalseOverride(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	writeTestFile(t, "gostub.yaml", `jobs:
+  - source: ./contracts.go
+    mode: per-method
+    ctx_aware: true
+    update: true
+`)
+
+	jobs, err := loadJobs(newTestContext(map[string]string{
+		optConfig:    "gostub.yaml",
+		"per-method": "false",
+		"update":     "",
+	}))
+	if err != nil {
+		t.Fatalf("loadJobs() error = %v", err)
+	}
+
+	for flag, want := range map[string]bool{
+		"per-method": false,
+		"ctx-aware":  true,
+		"update":     true,
+	} {
+		if got := hasOpt(jobs[0].Opts, flag); got != want {
+			t.Errorf("hasOpt(%q) = %v, want %v", flag, got, want)
+		}
+	}
+}
+
+func TestHasOpt(t *testing.T) {
+	opts := map[string]string{
+		"empty":    "",
+		"true":     "true",
+		"false":    "false",
+		"zero":     "0",
+		"not-bool": "value",
+	}
+
+	for name, want := range map[string]bool{
+		"empty":    true,
+		"true":     true,
+		"false":    false,
+		"zero":     false,
+		"not-bool": true,
+		"missing":  false,
+	} {
+		if got := hasOpt(opts, name); got != want {
+			t.Errorf("hasOpt(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -1,10 +1,29 @@
+// Code generated by gostub dev; DO NOT EDIT.
+//gostub:invocation ./api/contract.go --filename=impl.go --out=./api
+
 package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 )
 
+var ErrStubNotImplemented = errors.New("stub method not implemented")
+
+type NotImplementedError struct {
+	Type   string
+	Method string
+}
+
+func (e *NotImplementedError) Error() string {
+	return "method " + e.Type + "." + e.Method + " not implemented"
+}
+
+func (e *NotImplementedError) Unwrap() error {
+	return ErrStubNotImplemented
+}
+
 type StubUserService struct {
 }
 
@@ -13,9 +32,9 @@ func NewStubUserService() *StubUserService {
 }
 
 func (s *StubUserService) List(ctx context.Context, r *http.Request) (*Response, error) {
-	panic("method StubUserService.List not implemented")
+	panic(&NotImplementedError{Type: "StubUserService", Method: "List"})
 }
 
 func (s *StubUserService) Create(ctx context.Context, r *http.Request) (*Response, error) {
-	panic("method StubUserService.Create not implemented")
+	panic(&NotImplementedError{Type: "StubUserService", Method: "Create"})
 }
@@ -1,5 +1,27 @@
+// Code generated by gostub dev; DO NOT EDIT.
+//gostub:invocation ./contracts/service.go --filename=services.go --method-body=panic --out=./implementations --package=implementations --per-method '--per-method-filename={{ .Method.Name.Snake.Value }}.go'
+
 package implementations
 
+import (
+	"errors"
+)
+
+var ErrStubNotImplemented = errors.New("stub method not implemented")
+
+type NotImplementedError struct {
+	Type   string
+	Method string
+}
+
+func (e *NotImplementedError) Error() string {
+	return "method " + e.Type + "." + e.Method + " not implemented"
+}
+
+func (e *NotImplementedError) Unwrap() error {
+	return ErrStubNotImplemented
+}
+
 type StubUserService struct {
 }
 
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func generateCmd() *command {
+	return &command{
+		Name:        "generate",
+		Description: "generate code for source or for jobs of config, options override options of jobs",
+		Args:        []*cli.ArgDefinition{sourceArg()},
+		Opts:        generateOpts(),
+		Action:      generate,
+	}
+}
+
+func generate(ctx *cli.Context) error {
+	return runJobs(ctx, func(command *cmd.Command, params *cmd.Params) error {
+		return command.Run(ctx.Context, params)
+	})
+}
@@ -9,14 +9,14 @@ require (
 	github.com/artarts36/singlecli v0.0.0-20241017172045-f9a31a534745
 	github.com/fatih/camelcase v1.0.0
 	github.com/iancoleman/strcase v0.3.0
+	github.com/jinzhu/inflection v1.0.0
+	golang.org/x/mod v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/bahlo/generic-list-go v0.2.0 // indirect
 	github.com/buger/jsonparser v1.1.1 // indirect
-	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
-	golang.org/x/mod v0.21.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
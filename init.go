@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/artarts36/gostub/internal/config"
+	cli "github.com/artarts36/singlecli"
+)
+
+func initCmd() *command {
+	return &command{
+		Name:        "init",
+		Description: fmt.Sprintf("write starter config to %s or path of --config", config.DefaultFilename),
+		Action:      initConfig,
+	}
+}
+
+func initConfig(ctx *cli.Context) error {
+	path := configPath(ctx)
+
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("config %q already exists", path)
+	}
+
+	err := os.WriteFile(path, []byte(config.Starter), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	slog.InfoContext(ctx.Context, "[main] config created", slog.String("path", path))
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/artarts36/gostub/internal/golang"
+	st "github.com/artarts36/gostub/internal/stub"
+)
+
+var ErrStale = errors.New("generated files are stale")
+
+// check prints drifted interfaces and methods of planned changes.
+// Returns ErrStale when generated files differ from files on disk.
+func (c *Command) check(plan *Plan, stubs []*st.Stub) error {
+	changes := plan.Changes()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	interfaces := map[string]string{}
+	for _, stub := range stubs {
+		for _, typ := range stub.Types {
+			interfaces[typ.Name] = typ.Interface.Name.Value
+		}
+	}
+
+	out := strings.Builder{}
+
+	for _, file := range changes {
+		if file.Action == FileActionCreate {
+			out.WriteString(fmt.Sprintf("%s: missing\n", file.Path))
+
+			continue
+		}
+
+		out.WriteString(fmt.Sprintf("%s: stale\n", file.Path))
+
+		drifts, err := golang.CompareDecls(file.Path, file.Old, file.New)
+		if err != nil {
+			out.WriteString(fmt.Sprintf("    %s\n", err))
+
+			continue
+		}
+
+		if len(drifts) == 0 {
+			out.WriteString("    header or imports changed\n")
+		}
+
+		for _, drift := range drifts {
+			out.WriteString(fmt.Sprintf("    %s: %s\n", driftSubject(drift.Name, interfaces), drift.Change))
+		}
+	}
+
+	_, err := c.stdout.Write([]byte(out.String()))
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %d of %d files, regenerate them", ErrStale, len(changes), len(plan.Files))
+}
+
+// driftSubject replaces generated type name with interface name: StubUserService.Get -> UserService.Get.
+func driftSubject(declName string, interfaces map[string]string) string {
+	typeName, method, isMethod := strings.Cut(declName, ".")
+
+	iface, ok := interfaces[typeName]
+	if !ok {
+		return declName
+	}
+
+	if !isMethod {
+		return fmt.Sprintf("%s (type of %s)", declName, iface)
+	}
+
+	return fmt.Sprintf("%s.%s", iface, method)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const changedContracts = `package contracts
+
+import "context"
+
+type Users interface {
+	Get(ctx context.Context, id string) (string, error)
+	Create(ctx context.Context, name string) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, message string) error
+}
+`
+
+func TestCheckReportsDriftedMethods(t *testing.T) {
+	dir := newTestModule(t)
+
+	perType := func(params *Params) {
+		params.TypePerFile = true
+	}
+
+	command, params := newTestCommand(t, dir, "stubs", perType)
+
+	err := command.Run(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	users := filepath.Join(dir, "stubs", "users_stub.go")
+	generated := readTestFile(t, users)
+
+	writeTestFile(t, filepath.Join(dir, "contracts", "contracts.go"), changedContracts)
+
+	notifier := filepath.Join(dir, "stubs", "notifier_stub.go")
+
+	err = os.Remove(notifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	command, params = newTestCommand(t, dir, "stubs", func(params *Params) {
+		perType(params)
+		params.Check = true
+	})
+
+	err = command.Run(context.Background(), params)
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("Run() error = %v, want %v", err, ErrStale)
+	}
+
+	want := users + ": stale\n" +
+		"    Users.Get: changed\n" +
+		"    Users.Create: missing\n" +
+		"    Users.Delete: unexpected\n" +
+		notifier + ": missing\n"
+
+	if got := command.stdout.(*bytes.Buffer).String(); got != want {
+		t.Errorf("check printed:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := readTestFile(t, users); got != generated {
+		t.Errorf("check must not write files, got:\n%s", got)
+	}
+
+	if _, statErr := os.Stat(notifier); !os.IsNotExist(statErr) {
+		t.Errorf("check must not create files, stat error = %v", statErr)
+	}
+}
+
+func TestCheckDoesntReportFilesEditedByUser(t *testing.T) {
+	cases := []struct {
+		name      string
+		configure func(params *Params)
+		edited    string
+	}{
+		{
+			name: "skip exists",
+			configure: func(params *Params) {
+				params.SkipExists = true
+			},
+			edited: "stubs.go",
+		},
+		{
+			name: "editable per method",
+			configure: func(params *Params) {
+				params.MethodPerFile = true
+				params.EditablePerMethod = true
+			},
+			edited: "users_get_stub.go",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := newTestModule(t)
+
+			command, params := newTestCommand(t, dir, "stubs", c.configure)
+
+			err := command.Run(context.Background(), params)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			writeTestFile(t, filepath.Join(dir, "stubs", c.edited), "package stubs\n\n// edited\n")
+
+			command, params = newTestCommand(t, dir, "stubs", func(params *Params) {
+				c.configure(params)
+				params.Check = true
+			})
+
+			err = command.Run(context.Background(), params)
+			if err != nil {
+				t.Errorf("Run() error = %v, output:\n%s", err, command.stdout.(*bytes.Buffer).String())
+			}
+		})
+	}
+}
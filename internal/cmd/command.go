@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/artarts36/gomodfinder"
 	"github.com/artarts36/gostub/internal/golang"
 	"github.com/artarts36/gostub/internal/renderer"
 	st "github.com/artarts36/gostub/internal/stub"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -15,12 +17,15 @@ import (
 type Command struct {
 	renderer      *renderer.Renderer
 	stubCollector *st.Collector
+	stdout        io.Writer
 }
 
 type Params struct {
 	Source string
 
-	MethodBody string
+	Kind       *st.Kind
+	MethodBody *st.MethodBody
+	CtxAware   bool
 	Package    string
 
 	Filename string
@@ -35,15 +40,41 @@ type Params struct {
 
 	Out        string
 	SkipExists bool
+	Update     bool
+
+	// DryRun prints files which would be created, changed or kept, without writing them.
+	DryRun bool
+	// Diff prints unified diff of generated code with files on disk, without writing them.
+	Diff bool
+	// Check compares generated code with files on disk and fails when they differ, without writing them.
+	Check bool
+
+	Version           string
+	Invocation        *Invocation
+	EditablePerMethod bool
+	// KeepEditable skips existing editable per method files, used on regeneration.
+	KeepEditable bool
 
 	Interfaces     []string
 	SourceGoModule *gomodfinder.ModFile
 	TargetGoModule *gomodfinder.ModFile
+
+	names *golang.PackageNames
+}
+
+// packageNames returns resolver of names of packages imported by generated code.
+func (p *Params) packageNames() *golang.PackageNames {
+	if p.names == nil {
+		p.names = golang.NewPackageNames(p.TargetGoModule)
+	}
+
+	return p.names
 }
 
 func NewCommand(renderer *renderer.Renderer) *Command {
 	return &Command{
 		renderer: renderer,
+		stdout:   os.Stdout,
 	}
 }
 
@@ -52,6 +83,35 @@ func (c *Command) Run(ctx context.Context, params *Params) error {
 		With(slog.Any("params", params)).
 		InfoContext(ctx, "[command] running")
 
+	stubs, err := c.prepareStubs(params)
+	if err != nil {
+		return err
+	}
+
+	return c.generate(ctx, stubs, params)
+}
+
+// Regenerate writes only changed files and returns plan of generation, used by watch mode to report changes.
+func (c *Command) Regenerate(ctx context.Context, params *Params) (*Plan, error) {
+	stubs, err := c.prepareStubs(params)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := c.plan(ctx, stubs, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.write(ctx, plan, stubs, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (c *Command) prepareStubs(params *Params) ([]*st.Stub, error) {
 	nameGenerator, err := renderer.NewNameGenerator(
 		params.Filename,
 		params.PerMethodFilename,
@@ -59,20 +119,84 @@ func (c *Command) Run(ctx context.Context, params *Params) error {
 		params.TypeName,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create name generator: %w", err)
+		return nil, fmt.Errorf("failed to create name generator: %w", err)
+	}
+
+	if params.MethodBody.File != "" {
+		tpl, tplErr := os.ReadFile(params.MethodBody.File)
+		if tplErr != nil {
+			return nil, fmt.Errorf("failed to read method body template %q: %w", params.MethodBody.File, tplErr)
+		}
+
+		err = c.renderer.AddTemplate(params.MethodBody.Tpl, string(tpl))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add method body template: %w", err)
+		}
 	}
 
 	src, err := os.ReadFile(params.Source)
 	if err != nil {
-		return fmt.Errorf("failed to read %q: %w", params.Source, err)
+		return nil, fmt.Errorf("failed to read %q: %w", params.Source, err)
 	}
 
 	stubs, err := c.collectStubs(src, params, nameGenerator)
 	if err != nil {
-		return fmt.Errorf("failed to collect stubs: %w", err)
+		return nil, fmt.Errorf("failed to collect stubs: %w", err)
 	}
 
-	return c.generate(ctx, stubs, params)
+	err = c.skipDeclaredErrors(stubs, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return stubs, nil
+}
+
+// skipDeclaredErrors removes shared errors from stubs, when other files of target package already declare them,
+// e.g. files generated by another invocation into the same package.
+func (c *Command) skipDeclaredErrors(stubs []*st.Stub, params *Params) error {
+	if params.Kind.ErrorsTpl == "" {
+		return nil
+	}
+
+	dir := params.Out
+	if dir == "" {
+		dir = "."
+	}
+
+	own := map[string]bool{}
+	for _, stub := range stubs {
+		own[filepath.Join(dir, stub.Filename)] = true
+	}
+
+	declared, err := golang.DeclaredNames(dir, own)
+	if err != nil {
+		return fmt.Errorf("failed to collect declarations of target package: %w", err)
+	}
+
+	code := bytes.NewBufferString("package errors\n\n")
+
+	err = c.renderer.Render(code, params.Kind.ErrorsTpl, nil)
+	if err != nil {
+		return fmt.Errorf("failed to render errors: %w", err)
+	}
+
+	names, err := golang.DeclNames(params.Kind.ErrorsTpl, code.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to parse errors: %w", err)
+	}
+
+	for _, name := range names {
+		if !declared[name] {
+			return nil
+		}
+	}
+
+	for _, stub := range stubs {
+		stub.ErrorsTpl = ""
+	}
+
+	return nil
 }
 
 func (c *Command) generate(
@@ -80,42 +204,170 @@ func (c *Command) generate(
 	stubs []*st.Stub,
 	params *Params,
 ) error {
+	plan, err := c.plan(ctx, stubs, params)
+	if err != nil {
+		return err
+	}
+
+	if params.Check {
+		return c.check(plan, stubs)
+	}
+
+	if params.DryRun || params.Diff {
+		if params.DryRun {
+			err = plan.Print(c.stdout)
+			if err != nil {
+				return err
+			}
+		}
+
+		if params.Diff {
+			return plan.PrintDiff(c.stdout)
+		}
+
+		return nil
+	}
+
+	return c.write(ctx, plan, stubs, params)
+}
+
+// write applies planned changes and records generated files in manifest.
+func (c *Command) write(ctx context.Context, plan *Plan, stubs []*st.Stub, params *Params) error {
+	for _, file := range plan.Changes() {
+		slog.InfoContext(ctx, "[command] generating file", slog.String("file", file.Path))
+	}
+
+	err := plan.Apply()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := LoadManifest(filepath.Dir(params.TargetGoModule.Path))
+	if err != nil {
+		return err
+	}
+
+	err = manifest.record(plan, stubs, params)
+	if err != nil {
+		return err
+	}
+
+	return manifest.Save()
+}
+
+// plan renders stubs into memory and compares them with files on disk.
+func (c *Command) plan(
+	ctx context.Context,
+	stubs []*st.Stub,
+	params *Params,
+) (*Plan, error) {
+	plan := &Plan{}
+
 	for _, stub := range stubs {
 		filename := stub.Filename
 		if params.Out != "" {
 			filename = fmt.Sprintf("%s%s%s", params.Out, string(os.PathSeparator), filename)
 		}
 
-		if params.SkipExists {
-			if _, err := os.Stat(filename); err == nil {
-				continue
-			}
+		existing, err := readExisting(filename)
+		if err != nil {
+			return nil, err
 		}
 
-		slog.InfoContext(ctx, "[command] generating file", slog.String("file", filename))
+		keepEditable := (params.KeepEditable || params.Check) && params.EditablePerMethod && stub.PerMethod
 
-		file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-		if err != nil {
-			return fmt.Errorf("failed to open file %q: %w", stub.Filename, err)
+		if existing != nil && (params.SkipExists || keepEditable) {
+			plan.add(filename, existing, nil)
+
+			continue
 		}
 
-		err = c.renderer.Render(file, "stub.tpl", map[string]interface{}{
-			"Stub": stub,
-		})
+		if params.Invocation != nil && !(stub.PerMethod && params.EditablePerMethod) {
+			stub.Header = params.Invocation.Header(params.Version)
+		}
+
+		code, err := c.render(filename, stub, params)
 		if err != nil {
-			return fmt.Errorf("failed to render stub: %w", err)
+			return nil, err
+		}
+
+		if params.Update && existing != nil {
+			code, err = c.mergeExisting(ctx, filename, existing, code, stub, params)
+			if err != nil {
+				return nil, err
+			}
 		}
+
+		plan.add(filename, existing, code)
 	}
 
-	return nil
+	for i, file := range plan.Files {
+		file.Interfaces = stubInterfaces(stubs[i])
+	}
+
+	return plan, nil
 }
 
-func (c *Command) collectStubs(src []byte, params *Params, nameGenerator *renderer.NameGenerator) ([]*st.Stub, error) {
-	methodBodyTpl := "method_body_panic.tpl"
-	if params.MethodBody == "panic" {
-		methodBodyTpl = "method_body_nil_returns.tpl"
+func (c *Command) render(filename string, stub *st.Stub, params *Params) ([]byte, error) {
+	code := bytes.Buffer{}
+
+	err := c.renderer.Render(&code, "stub.tpl", map[string]interface{}{
+		"Stub": stub,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render stub: %w", err)
+	}
+
+	formatted, err := golang.FormatSource(golang.FormatParams{
+		Filename:   filename,
+		Source:     code.Bytes(),
+		Module:     params.TargetGoModule.Module.Mod.Path,
+		Candidates: stub.ImportCandidates(),
+
+		PackageNames: params.packageNames(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generated code is invalid: %w", err)
+	}
+
+	return formatted, nil
+}
+
+// mergeExisting adds missing declarations of generated code to existing file.
+// Returns nil when file is up-to-date.
+func (c *Command) mergeExisting(
+	ctx context.Context,
+	filename string,
+	existing []byte,
+	generated []byte,
+	stub *st.Stub,
+	params *Params,
+) ([]byte, error) {
+	merged, added, err := golang.MergeSource(golang.MergeParams{
+		Filename:   filename,
+		Existing:   existing,
+		Generated:  generated,
+		Module:     params.TargetGoModule.Module.Mod.Path,
+		Candidates: stub.ImportCandidates(),
+
+		PackageNames: params.packageNames(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge file %q: %w", filename, err)
 	}
 
+	if len(added) == 0 {
+		slog.InfoContext(ctx, "[command] file is up-to-date", slog.String("file", filename))
+
+		return nil, nil
+	}
+
+	slog.InfoContext(ctx, "[command] adding declarations", slog.String("file", filename), slog.Any("declarations", added))
+
+	return merged, nil
+}
+
+func (c *Command) collectStubs(src []byte, params *Params, nameGenerator *renderer.NameGenerator) ([]*st.Stub, error) {
 	sourceAbsPath, err := filepath.Abs(params.Source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absoulte source path for source %q: %w", sourceAbsPath, err)
@@ -126,16 +378,15 @@ func (c *Command) collectStubs(src []byte, params *Params, nameGenerator *render
 		SourcePath:     sourceAbsPath,
 		FilterNames:    params.Interfaces,
 		SourceGoModule: params.SourceGoModule,
+		TypeCheck:      params.MethodBody.TypeCheck,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse go file: %w", err)
 	}
 
-	var targetPkg *gomodfinder.Package
-	if params.Package != "" {
-		targetPkg = params.TargetGoModule.Package(params.Package)
-	} else {
-		targetPkg = parsedFile.Interfaces[0].Package
+	targetPkg, err := c.resolveTargetPackage(params, parsedFile.Interfaces[0].Package)
+	if err != nil {
+		return nil, err
 	}
 
 	return c.stubCollector.Collect(&st.CollectParams{
@@ -144,8 +395,58 @@ func (c *Command) collectStubs(src []byte, params *Params, nameGenerator *render
 		TypePerFile:   params.TypePerFile,
 		MethodPerFile: params.MethodPerFile,
 
-		MethodBodyTpl: methodBodyTpl,
+		Kind:          params.Kind,
+		MethodBodyTpl: params.MethodBody.Tpl,
+		CtxAware:      params.CtxAware,
 
 		TargetPackage: targetPkg,
 	}, nameGenerator)
 }
+
+// resolveTargetPackage returns package of generated files: package from --package, package of files in --out,
+// package named by --out directory or source package.
+func (c *Command) resolveTargetPackage(params *Params, sourcePkg *gomodfinder.Package) (*gomodfinder.Package, error) {
+	if params.Out == "" {
+		if params.Package != "" {
+			return params.TargetGoModule.Package(params.Package), nil
+		}
+
+		return sourcePkg, nil
+	}
+
+	existingName, err := golang.DetectPackageName(params.Out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect package of out directory: %w", err)
+	}
+
+	if params.Package != "" {
+		pkg := params.TargetGoModule.Package(params.Package)
+		if existingName != "" && existingName != pkg.Name {
+			return nil, fmt.Errorf(
+				"--package=%s conflicts with package %q of files in %q",
+				params.Package,
+				existingName,
+				params.Out,
+			)
+		}
+
+		return pkg, nil
+	}
+
+	outAbsPath, err := filepath.Abs(params.Out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of out directory %q: %w", params.Out, err)
+	}
+
+	name := existingName
+	if name == "" {
+		name = golang.PackageNameFromDir(outAbsPath)
+	}
+
+	pkg := params.TargetGoModule.CalcPackageFromAbsPathWithName(name, outAbsPath)
+	if pkg.Equal(sourcePkg) {
+		return sourcePkg, nil
+	}
+
+	return pkg, nil
+}
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/artarts36/gomodfinder"
+	"github.com/artarts36/gostub/internal/renderer"
+	st "github.com/artarts36/gostub/internal/stub"
+)
+
+const testContracts = `package contracts
+
+import "context"
+
+type Users interface {
+	Get(ctx context.Context, id int) (string, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, message string) error
+}
+`
+
+// newTestModule creates module with contracts package, returns root of module.
+func newTestModule(t *testing.T) string {
+	t.Helper()
+
+	return newTestModuleWithContracts(t, testContracts)
+}
+
+// newTestModuleWithContracts creates module with contracts package of given code, returns root of module.
+func newTestModuleWithContracts(t *testing.T, contracts string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/m\n\ngo 1.22\n")
+	writeTestFile(t, filepath.Join(dir, "contracts", "contracts.go"), contracts)
+
+	return dir
+}
+
+// assertCompiles runs go vet and tests of module, module must not have dependencies.
+func assertCompiles(t *testing.T, dir string) {
+	t.Helper()
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go isn't found in PATH")
+	}
+
+	for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+		command := exec.Command(goBin, args...)
+		command.Dir = dir
+		command.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOPROXY=off")
+
+		out, runErr := command.CombinedOutput()
+		if runErr != nil {
+			t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), runErr, out)
+		}
+	}
+}
+
+// newTestCommand creates command and params generating stubs of contracts of module into out directory.
+func newTestCommand(t *testing.T, dir, out string, configure func(params *Params)) (*Command, *Params) {
+	t.Helper()
+
+	rend, err := renderer.NewRenderer("")
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+
+	goMod, err := gomodfinder.Find(dir, 1)
+	if err != nil {
+		t.Fatalf("failed to find go.mod: %v", err)
+	}
+
+	kind, err := st.FindKind("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	methodBody, err := st.FindMethodBody("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	command := NewCommand(rend)
+	command.stdout = &bytes.Buffer{}
+
+	params := &Params{
+		Source:            filepath.Join(dir, "contracts", "contracts.go"),
+		Kind:              kind,
+		MethodBody:        methodBody,
+		Filename:          kind.DefaultFilename,
+		PerMethodFilename: "{{ .Interface.Name.Snake.Value }}_{{ .Method.Name.Snake.Value }}_stub.go",
+		PerTypeFilename:   "{{ .Interface.Name.Snake.Value }}_stub.go",
+		TypeName:          kind.DefaultTypeName,
+		Out:               filepath.Join(dir, out),
+		SourceGoModule:    goMod,
+		TargetGoModule:    goMod,
+	}
+
+	if configure != nil {
+		configure(params)
+	}
+
+	return command, params
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+func TestRunDeclaresSharedErrorsOncePerPackage(t *testing.T) {
+	dir := newTestModule(t)
+
+	for _, job := range []struct {
+		filename   string
+		interfaces []string
+	}{
+		{filename: "users.go", interfaces: []string{"Users"}},
+		{filename: "notifier.go", interfaces: []string{"Notifier"}},
+		// regeneration of the first file must keep errors in it
+		{filename: "users.go", interfaces: []string{"Users"}},
+	} {
+		command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+			params.Filename = job.filename
+			params.Interfaces = job.interfaces
+		})
+
+		err := command.Run(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Run() of %s error = %v", job.filename, err)
+		}
+	}
+
+	users := readTestFile(t, filepath.Join(dir, "stubs", "users.go"))
+	notifier := readTestFile(t, filepath.Join(dir, "stubs", "notifier.go"))
+
+	if strings.Count(users, "var ErrStubNotImplemented") != 1 {
+		t.Errorf("users.go must declare shared errors:\n%s", users)
+	}
+
+	if strings.Contains(notifier, "ErrStubNotImplemented =") || strings.Contains(notifier, "type NotImplementedError") {
+		t.Errorf("notifier.go must not redeclare shared errors:\n%s", notifier)
+	}
+}
+
+func TestRunKeepsFilesGeneratedByAnotherVersion(t *testing.T) {
+	dir := newTestModule(t)
+
+	run := func(version string, check bool) error {
+		command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+			var err error
+
+			params.Invocation, err = NewInvocation(dir, params.Source, map[string]string{"out": params.Out})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params.Version = version
+			params.Check = check
+		})
+
+		return command.Run(context.Background(), params)
+	}
+
+	err := run("v1.0.0", false)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	path := filepath.Join(dir, "stubs", "stubs.go")
+	generated := readTestFile(t, path)
+
+	err = run("v1.1.0", true)
+	if err != nil {
+		t.Errorf("check of files generated by another version error = %v", err)
+	}
+
+	err = run("v1.1.0", false)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := readTestFile(t, path); got != generated {
+		t.Errorf("file generated by another version is rewritten:\n%s", got)
+	}
+}
+
+func TestRunWritesHeaderWithInvocation(t *testing.T) {
+	dir := newTestModule(t)
+
+	var invocation *Invocation
+
+	command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+		var err error
+
+		invocation, err = NewInvocation(dir, params.Source, map[string]string{
+			"out":                 params.Out,
+			"interfaces":          "Users",
+			"per-method":          "",
+			"editable-per-method": "",
+			"type-name":           "Fake{{ .Interface.Name.Pascal.Value }}",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		params.Invocation = invocation
+		params.Version = "v1.0.0"
+		params.Interfaces = []string{"Users"}
+		params.MethodPerFile = true
+		params.EditablePerMethod = true
+		params.TypeName = "Fake{{ .Interface.Name.Pascal.Value }}"
+	})
+
+	err := command.Run(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	common := readTestFile(t, filepath.Join(dir, "stubs", "stubs.go"))
+	if !strings.HasPrefix(common, "// Code generated by gostub v1.0.0; DO NOT EDIT.\n") {
+		t.Errorf("common file must start with generated header:\n%s", common)
+	}
+
+	parsed, err := ParseHeader([]byte(common))
+	if err != nil {
+		t.Fatalf("ParseHeader() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(parsed, invocation) {
+		t.Errorf("ParseHeader() = %+v, want %+v", parsed, invocation)
+	}
+
+	for _, filename := range []string{"users_get_stub.go", "users_delete_stub.go"} {
+		editable := readTestFile(t, filepath.Join(dir, "stubs", filename))
+		if strings.Contains(editable, "DO NOT EDIT") || strings.Contains(editable, invocationDirective) {
+			t.Errorf("editable file %s must not have generated header:\n%s", filename, editable)
+		}
+	}
+
+	assertCompiles(t, dir)
+}
+
+func TestRunInfersPackageOfOutDirectory(t *testing.T) {
+	dir := newTestModule(t)
+
+	writeTestFile(t, filepath.Join(dir, "implementations", "users.go"), "package impl\n")
+
+	for out, wantPackage := range map[string]string{
+		"implementations":                   "impl",
+		filepath.Join("gen", "rpc-service"): "rpcservice",
+	} {
+		command, params := newTestCommand(t, dir, out, nil)
+
+		err := command.Run(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Run() into %s error = %v", out, err)
+		}
+
+		generated := readTestFile(t, filepath.Join(dir, out, "stubs.go"))
+		if !strings.HasPrefix(generated, "package "+wantPackage+"\n") {
+			t.Errorf("file in %s must have package %s:\n%s", out, wantPackage, generated)
+		}
+	}
+
+	assertCompiles(t, dir)
+}
+
+func TestRunFailsOnPackageConflictingWithOutDirectory(t *testing.T) {
+	dir := newTestModule(t)
+
+	writeTestFile(t, filepath.Join(dir, "implementations", "users.go"), "package impl\n")
+
+	command, params := newTestCommand(t, dir, "implementations", func(params *Params) {
+		params.Package = "stubs"
+	})
+
+	err := command.Run(context.Background(), params)
+	if err == nil || !strings.Contains(err.Error(), `--package=stubs conflicts with package "impl"`) {
+		t.Errorf("Run() error = %v, want conflict of packages", err)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns lines "l<number>" from 1 to count, replaced lines are set by their numbers.
+func numberedLines(count int, replace map[int]string) string {
+	out := strings.Builder{}
+
+	for i := 1; i <= count; i++ {
+		line, ok := replace[i]
+		if !ok {
+			line = fmt.Sprintf("l%d", i)
+		}
+
+		out.WriteString(line + "\n")
+	}
+
+	return out.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			old:  "a\nb\nc\n",
+			new:  "a\nB\nc\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "new file",
+			old:  "",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "removed lines",
+			old:  "a\nb\nc\nd\ne\nf\n",
+			new:  "a\nf\n",
+			want: "--- old\n+++ new\n@@ -1,6 +1,2 @@\n a\n-b\n-c\n-d\n-e\n f\n",
+		},
+		{
+			name: "separate hunks",
+			old:  numberedLines(20, nil),
+			new:  numberedLines(20, map[int]string{2: "X", 19: "Y"}),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,5 +1,5 @@\n l1\n-l2\n+X\n l3\n l4\n l5\n" +
+				"@@ -16,5 +16,5 @@\n l16\n l17\n l18\n-l19\n+Y\n l20\n",
+		},
+		{
+			name: "close changes in one hunk",
+			old:  numberedLines(12, nil),
+			new:  numberedLines(12, map[int]string{3: "X", 7: "Y"}),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,10 +1,10 @@\n l1\n l2\n-l3\n+X\n l4\n l5\n l6\n-l7\n+Y\n l8\n l9\n l10\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var old []byte
+			if c.old != "" {
+				old = []byte(c.old)
+			}
+
+			got := unifiedDiff("old", "new", old, []byte(c.new))
+			if got != c.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
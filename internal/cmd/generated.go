@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/artarts36/gomodfinder"
+)
+
+const findGoModLevels = 10
+
+// GeneratedGroup is set of files generated by one invocation.
+type GeneratedGroup struct {
+	// ModuleDir is root of target module, invocation must be run from it.
+	ModuleDir  string
+	Invocation *Invocation
+	Files      []string
+}
+
+// FindGenerated scans files by pattern ("./...", "./dir/...", "./dir" or "./file.go")
+// and groups files generated by gostub by their invocation.
+func FindGenerated(pattern string) ([]*GeneratedGroup, error) {
+	files, err := matchGoFiles(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]*GeneratedGroup{}
+
+	for _, file := range files {
+		src, readErr := os.ReadFile(file)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", file, readErr)
+		}
+
+		inv, parseErr := ParseHeader(src)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse header of %q: %w", file, parseErr)
+		}
+
+		if inv == nil {
+			continue
+		}
+
+		goMod, modErr := gomodfinder.Find(filepath.Dir(file), findGoModLevels)
+		if modErr != nil {
+			return nil, fmt.Errorf("failed to find go.mod for %q: %w", file, modErr)
+		}
+
+		moduleDir := filepath.Dir(goMod.Path)
+		key := moduleDir + "\x00" + inv.ShellArgs()
+
+		group, ok := groups[key]
+		if !ok {
+			group = &GeneratedGroup{
+				ModuleDir:  moduleDir,
+				Invocation: inv,
+			}
+			groups[key] = group
+		}
+
+		group.Files = append(group.Files, file)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([]*GeneratedGroup, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, groups[key])
+	}
+
+	return result, nil
+}
+
+func matchGoFiles(pattern string) ([]string, error) {
+	root, recursive := strings.CutSuffix(pattern, "...")
+	if root == "" {
+		root = "."
+	}
+
+	root = filepath.Clean(root)
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %q: %w", root, err)
+	}
+
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+
+	files := make([]string, 0)
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+
+			if !recursive || skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if strings.HasSuffix(path, ".go") {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %q: %w", root, err)
+	}
+
+	return files, nil
+}
+
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindGenerated(t *testing.T) {
+	dir := t.TempDir()
+
+	header := func(args string) string {
+		return "// Code generated by gostub v1.0.0; DO NOT EDIT.\n" + invocationDirective + args + "\n\npackage stubs\n"
+	}
+
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/m\n\ngo 1.22\n")
+
+	for path, content := range map[string]string{
+		"stubs/users.go":             header("./contracts/a.go --out=./stubs --per-method"),
+		"stubs/users_get.go":         header("./contracts/a.go --out=./stubs --per-method"),
+		"stubs/edited.go":            "package stubs\n",
+		"fakes/fakes.go":             header("./contracts/a.go --out=./fakes --kind=fake"),
+		"vendor/example.com/x/x.go":  header("./x.go"),
+		"stubs/testdata/old_stub.go": header("./old.go"),
+	} {
+		writeTestFile(t, filepath.Join(dir, path), content)
+	}
+
+	groups, err := FindGenerated(dir + "/...")
+	if err != nil {
+		t.Fatalf("FindGenerated() error = %v", err)
+	}
+
+	want := []*GeneratedGroup{
+		{
+			ModuleDir:  dir,
+			Invocation: &Invocation{Source: "./contracts/a.go", Opts: map[string]string{"out": "./fakes", "kind": "fake"}},
+			Files:      []string{filepath.Join(dir, "fakes", "fakes.go")},
+		},
+		{
+			ModuleDir:  dir,
+			Invocation: &Invocation{Source: "./contracts/a.go", Opts: map[string]string{"out": "./stubs", "per-method": ""}},
+			Files:      []string{filepath.Join(dir, "stubs", "users.go"), filepath.Join(dir, "stubs", "users_get.go")},
+		},
+	}
+
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("FindGenerated() = %+v, want %+v", groups, want)
+	}
+
+	groups, err = FindGenerated(filepath.Join(dir, "fakes"))
+	if err != nil {
+		t.Fatalf("FindGenerated() error = %v", err)
+	}
+
+	if len(groups) != 1 || !reflect.DeepEqual(groups[0].Files, []string{filepath.Join(dir, "fakes", "fakes.go")}) {
+		t.Errorf("FindGenerated() of directory = %+v", groups)
+	}
+}
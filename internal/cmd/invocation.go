@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const generatedHeader = "// Code generated by gostub %s; DO NOT EDIT."
+
+// invocationDirective keeps invocation in generated file, paths are relative to root of target module.
+const invocationDirective = "//gostub:invocation "
+
+// pathOpts contains options with paths which must be relative to root of target module.
+var pathOpts = map[string]bool{
+	"out":       true,
+	"templates": true,
+}
+
+// transientOpts contains options, which don't affect generated code and aren't kept in invocation.
+var transientOpts = map[string]bool{
+	"dry-run": true,
+	"diff":    true,
+	"check":   true,
+}
+
+var (
+	safeShellArgRegexp    = regexp.MustCompile(`^[a-zA-Z0-9_./:=,+-]+$`)
+	generatedHeaderRegexp = regexp.MustCompile(`(?m)^// Code generated by gostub .*; DO NOT EDIT\.$`)
+)
+
+// Invocation is gostub call which generates file.
+type Invocation struct {
+	Source string
+	Opts   map[string]string
+}
+
+// NewInvocation creates invocation with paths relative to moduleDir.
+func NewInvocation(moduleDir string, source string, opts map[string]string) (*Invocation, error) {
+	inv := &Invocation{
+		Opts: make(map[string]string, len(opts)),
+	}
+
+	var err error
+
+	inv.Source, err = relativePath(moduleDir, source)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range opts {
+		if transientOpts[name] {
+			continue
+		}
+
+		value, err = convertPathOpt(name, value, func(path string) (string, error) {
+			return relativePath(moduleDir, path)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		inv.Opts[name] = value
+	}
+
+	return inv, nil
+}
+
+// AbsPathOpts returns options with paths resolved from working directory,
+// used to keep paths of command line when job runs in another directory.
+func AbsPathOpts(opts map[string]string) (map[string]string, error) {
+	abs := make(map[string]string, len(opts))
+
+	for name, value := range opts {
+		value, err := convertPathOpt(name, value, func(path string) (string, error) {
+			absPath, absErr := filepath.Abs(path)
+			if absErr != nil {
+				return "", fmt.Errorf("failed to get absolute path of %q: %w", path, absErr)
+			}
+
+			return absPath, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		abs[name] = value
+	}
+
+	return abs, nil
+}
+
+// convertPathOpt converts path of option with path, values of other options are returned as is.
+func convertPathOpt(name, value string, convert func(path string) (string, error)) (string, error) {
+	switch {
+	case pathOpts[name] && value != "":
+		return convert(value)
+	case name == "method-body" && strings.HasPrefix(value, "file:"):
+		path, err := convert(strings.TrimPrefix(value, "file:"))
+
+		return "file:" + path, err
+	}
+
+	return value, nil
+}
+
+// Args returns arguments of invocation: source and sorted options.
+func (i *Invocation) Args() []string {
+	names := make([]string, 0, len(i.Opts))
+	for name := range i.Opts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	args := make([]string, 0, len(names)+1)
+	args = append(args, i.Source)
+
+	for _, name := range names {
+		if i.Opts[name] == "" {
+			args = append(args, "--"+name)
+			continue
+		}
+
+		args = append(args, fmt.Sprintf("--%s=%s", name, i.Opts[name]))
+	}
+
+	return args
+}
+
+// ShellArgs returns arguments quoted for shell.
+func (i *Invocation) ShellArgs() string {
+	args := i.Args()
+
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, quoteShellArg(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func (i *Invocation) String() string {
+	return "gostub " + i.ShellArgs()
+}
+
+// Header returns header of generated file.
+func (i *Invocation) Header(version string) string {
+	return fmt.Sprintf(generatedHeader, version) + "\n" + invocationDirective + i.ShellArgs()
+}
+
+// withoutVersion removes version of gostub from generated header, so code generated by different versions is comparable.
+func withoutVersion(src []byte) []byte {
+	return generatedHeaderRegexp.ReplaceAllLiteral(src, []byte(fmt.Sprintf(generatedHeader, "")))
+}
+
+func relativePath(moduleDir, path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path of %q: %w", path, err)
+	}
+
+	rel, err := filepath.Rel(moduleDir, absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get path of %q relative to %q: %w", path, moduleDir, err)
+	}
+
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+
+	return rel, nil
+}
+
+func quoteShellArg(arg string) string {
+	if safeShellArgRegexp.MatchString(arg) {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// ParseHeader returns invocation from header of file generated by gostub.
+// Returns nil, when file isn't generated by gostub or doesn't keep invocation.
+func ParseHeader(src []byte) (*Invocation, error) {
+	lines := strings.Split(string(src), "\n")
+
+	generated := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case generatedHeaderRegexp.MatchString(line):
+			generated = true
+		case generated && strings.HasPrefix(line, invocationDirective):
+			return ParseInvocation(strings.TrimPrefix(line, invocationDirective))
+		case strings.HasPrefix(line, "package "):
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ParseInvocation parses shell arguments of invocation.
+func ParseInvocation(shellArgs string) (*Invocation, error) {
+	args, err := splitShellArgs(shellArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &Invocation{
+		Opts: map[string]string{},
+	}
+
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			if inv.Source != "" {
+				return nil, fmt.Errorf("unexpected argument %q", arg)
+			}
+
+			inv.Source = arg
+
+			continue
+		}
+
+		name, value, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		inv.Opts[name] = value
+	}
+
+	if inv.Source == "" {
+		return nil, fmt.Errorf("invocation %q doesn't contain source", shellArgs)
+	}
+
+	return inv, nil
+}
+
+func splitShellArgs(str string) ([]string, error) {
+	args := make([]string, 0)
+
+	var (
+		current  strings.Builder
+		inArg    bool
+		quote    rune
+		escaping bool
+	)
+
+	for _, r := range str {
+		switch {
+		case escaping:
+			current.WriteRune(r)
+			escaping = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				escaping = true
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\':
+			escaping = true
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaping {
+		return nil, fmt.Errorf("unterminated quote in %q", str)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
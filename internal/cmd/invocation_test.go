@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInvocationShellArgs(t *testing.T) {
+	cases := []struct {
+		name string
+		inv  *Invocation
+		want string
+	}{
+		{
+			name: "sorted options",
+			inv: &Invocation{
+				Source: "./contracts/contracts.go",
+				Opts:   map[string]string{"out": "./stubs", "kind": "fallback", "per-type": ""},
+			},
+			want: "./contracts/contracts.go --kind=fallback --out=./stubs --per-type",
+		},
+		{
+			name: "quoted options",
+			inv: &Invocation{
+				Source: "./contracts.go",
+				Opts: map[string]string{
+					"filename":   "{{ .Interface.Name.Snake.Value }}.go",
+					"interfaces": "Users, Notifier",
+					"package":    "it's",
+				},
+			},
+			want: `./contracts.go '--filename={{ .Interface.Name.Snake.Value }}.go' '--interfaces=Users, Notifier' '--package=it'\''s'`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.inv.ShellArgs()
+			if got != c.want {
+				t.Errorf("ShellArgs() = %s, want %s", got, c.want)
+			}
+
+			parsed, err := ParseInvocation(got)
+			if err != nil {
+				t.Fatalf("ParseInvocation() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(parsed, c.inv) {
+				t.Errorf("ParseInvocation() = %+v, want %+v", parsed, c.inv)
+			}
+		})
+	}
+}
+
+func TestParseInvocation(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    string
+		want    *Invocation
+		wantErr bool
+	}{
+		{
+			name: "double quotes with escaping",
+			args: `./a.go "--type-name=A \"B\"" --update`,
+			want: &Invocation{Source: "./a.go", Opts: map[string]string{"type-name": `A "B"`, "update": ""}},
+		},
+		{
+			name: "escaped space",
+			args: `./a\ b.go`,
+			want: &Invocation{Source: "./a b.go", Opts: map[string]string{}},
+		},
+		{name: "without source", args: "--update", wantErr: true},
+		{name: "two sources", args: "./a.go ./b.go", wantErr: true},
+		{name: "unclosed quote", args: "./a.go '--out=x", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseInvocation(c.args)
+			if c.wantErr {
+				if err == nil {
+					t.Errorf("ParseInvocation() = %+v, want error", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseInvocation() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("ParseInvocation() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want *Invocation
+	}{
+		{
+			name: "generated",
+			src: "// Code generated by gostub v1.0.0; DO NOT EDIT.\n" +
+				invocationDirective + "./a.go --out=./stubs\n\npackage stubs\n",
+			want: &Invocation{Source: "./a.go", Opts: map[string]string{"out": "./stubs"}},
+		},
+		{
+			name: "without invocation",
+			src:  "// Code generated by gostub v1.0.0; DO NOT EDIT.\n\npackage stubs\n",
+		},
+		{
+			name: "not generated",
+			src:  invocationDirective + "./a.go\n\npackage stubs\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseHeader([]byte(c.src))
+			if err != nil {
+				t.Fatalf("ParseHeader() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("ParseHeader() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNewInvocation(t *testing.T) {
+	dir := t.TempDir()
+
+	inv, err := NewInvocation(dir, filepath.Join(dir, "contracts", "a.go"), map[string]string{
+		"out":         filepath.Join(dir, "stubs"),
+		"method-body": "file:" + filepath.Join(dir, "body.tpl"),
+		"kind":        "stub",
+		"dry-run":     "",
+	})
+	if err != nil {
+		t.Fatalf("NewInvocation() error = %v", err)
+	}
+
+	want := &Invocation{
+		Source: "./contracts/a.go",
+		Opts:   map[string]string{"out": "./stubs", "method-body": "file:./body.tpl", "kind": "stub"},
+	}
+
+	if !reflect.DeepEqual(inv, want) {
+		t.Errorf("NewInvocation() = %+v, want %+v", inv, want)
+	}
+}
+
+func TestAbsPathOpts(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := AbsPathOpts(map[string]string{
+		"out":         "stubs",
+		"templates":   "/tpl",
+		"method-body": "file:body.tpl",
+		"kind":        "stub",
+		"package":     "",
+	})
+	if err != nil {
+		t.Fatalf("AbsPathOpts() error = %v", err)
+	}
+
+	want := map[string]string{
+		"out":         filepath.Join(wd, "stubs"),
+		"templates":   "/tpl",
+		"method-body": "file:" + filepath.Join(wd, "body.tpl"),
+		"kind":        "stub",
+		"package":     "",
+	}
+
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("AbsPathOpts() = %v, want %v", opts, want)
+	}
+}
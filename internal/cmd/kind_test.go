@@ -0,0 +1,684 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	st "github.com/artarts36/gostub/internal/stub"
+)
+
+// generateKind generates code of kind for contracts of module into stubs package.
+func generateKind(t *testing.T, dir, kind string, configure func(params *Params)) {
+	t.Helper()
+
+	command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+		var err error
+
+		params.Kind, err = st.FindKind(kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		params.Filename = params.Kind.DefaultFilename
+		params.TypeName = params.Kind.DefaultTypeName
+
+		if configure != nil {
+			configure(params)
+		}
+	})
+
+	err := command.Run(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Run() of kind %s error = %v", kind, err)
+	}
+}
+
+const singleflightContracts = `package contracts
+
+type Finder interface {
+	Find(a string, b string) (int, error)
+	Count(result string) (int, error)
+	Sum(r0 int, r1 int) (int, error)
+	Named(id int) (result int, err error)
+	Reset()
+}
+`
+
+func TestSingleflightKind(t *testing.T) {
+	dir := newTestModuleWithContracts(t, singleflightContracts)
+
+	generateKind(t, dir, st.KindSingleflight, nil)
+
+	writeTestFile(t, filepath.Join(dir, "stubs", "key_test.go"), `package stubs
+
+import "testing"
+
+func TestKeyOfDifferentArgs(t *testing.T) {
+	if singleflightFinderKey("Find", "a b", "c") == singleflightFinderKey("Find", "a", "b c") {
+		t.Error("different arguments have equal keys")
+	}
+
+	if singleflightFinderKey("Find", "a", "b") != singleflightFinderKey("Find", "a", "b") {
+		t.Error("equal arguments have different keys")
+	}
+}
+`)
+
+	assertCompiles(t, dir)
+}
+
+func TestSingleflightKindCollapsesConcurrentCalls(t *testing.T) {
+	dir := newTestModuleWithContracts(t, singleflightContracts)
+
+	generateKind(t, dir, st.KindSingleflight, nil)
+
+	writeTestFile(t, filepath.Join(dir, "stubs", "collapse_test.go"), `package stubs
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type finder struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (f *finder) Find(a string, b string) (int, error) {
+	f.calls.Add(1)
+	<-f.release
+
+	return len(a + b), nil
+}
+
+func (f *finder) Count(string) (int, error) { return 0, nil }
+
+func (f *finder) Sum(int, int) (int, error) { return 0, nil }
+
+func (f *finder) Named(int) (int, error) { return 0, nil }
+
+func (f *finder) Reset() {}
+
+func TestConcurrentCallsAreCollapsed(t *testing.T) {
+	next := &finder{release: make(chan struct{})}
+
+	var keys atomic.Int32
+
+	flight := NewSingleflightFinder(next, func(method string, args ...any) string {
+		keys.Add(1)
+
+		return method
+	})
+
+	results := make([]int, 5)
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// custom key ignores arguments, so different calls are collapsed too
+			results[i], _ = flight.Find("a", strings.Repeat("b", i))
+		}()
+	}
+
+	for keys.Load() < int32(len(results)) {
+		time.Sleep(time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	if got := next.calls.Load(); got != 1 {
+		t.Fatalf("next is called %d times, want 1", got)
+	}
+
+	for i, result := range results {
+		if result != results[0] {
+			t.Errorf("result of call %d = %d, want shared result %d", i, result, results[0])
+		}
+	}
+}
+`)
+
+	assertCompiles(t, dir)
+}
+
+const lazyContracts = `package contracts
+
+type Store interface {
+	Put(impl string, err error) error
+	Get(id int) (impl string, err error)
+	Close()
+}
+`
+
+func TestLazyKind(t *testing.T) {
+	dir := newTestModuleWithContracts(t, lazyContracts)
+
+	generateKind(t, dir, st.KindLazy, nil)
+
+	assertCompiles(t, dir)
+}
+
+func TestLazyKindCreatesImplementationOnce(t *testing.T) {
+	dir := newTestModuleWithContracts(t, lazyContracts)
+
+	generateKind(t, dir, st.KindLazy, nil)
+
+	writeTestFile(t, filepath.Join(dir, "stubs", "lazy_test.go"), `package stubs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"example.com/m/contracts"
+)
+
+type store struct{}
+
+func (store) Put(string, error) error { return nil }
+
+func (store) Get(id int) (string, error) { return strings.Repeat("v", id), nil }
+
+func (store) Close() {}
+
+func TestImplementationIsCreatedOnFirstCall(t *testing.T) {
+	created := 0
+
+	lazy := NewLazyStore(func() (contracts.Store, error) {
+		created++
+
+		return store{}, nil
+	})
+
+	if created != 0 {
+		t.Fatal("implementation is created before first call")
+	}
+
+	for i := 1; i <= 2; i++ {
+		value, err := lazy.Get(i)
+		if err != nil || value != strings.Repeat("v", i) {
+			t.Fatalf("Get(%d) = (%q, %v)", i, value, err)
+		}
+	}
+
+	if created != 1 {
+		t.Errorf("implementation is created %d times, want 1", created)
+	}
+}
+
+func TestErrorOfFactoryIsReturned(t *testing.T) {
+	errFactory := errors.New("no connection")
+
+	lazy := NewLazyStore(func() (contracts.Store, error) {
+		return nil, errFactory
+	})
+
+	if err := lazy.Put("v", nil); !errors.Is(err, errFactory) {
+		t.Errorf("Put() error = %v, want %v", err, errFactory)
+	}
+
+	if _, err := lazy.Get(1); !errors.Is(err, errFactory) {
+		t.Errorf("Get() error = %v, want %v", err, errFactory)
+	}
+
+	defer func() {
+		message, _ := recover().(string)
+		if message != "LazyStore.Close: failed to create Store: no connection" {
+			t.Errorf("Close() panics with %q", message)
+		}
+	}()
+
+	lazy.Close()
+}
+`)
+
+	assertCompiles(t, dir)
+}
+
+const guardedContracts = `package contracts
+
+import "context"
+
+type Users interface {
+	//gostub:policy users.write
+	Create(ctx context.Context, name string) (int, error)
+	Get(id int) (string, error)
+	Reset()
+}
+`
+
+func TestGuardedKind(t *testing.T) {
+	dir := newTestModuleWithContracts(t, guardedContracts)
+
+	generateKind(t, dir, st.KindGuarded, nil)
+
+	writeTestFile(t, filepath.Join(dir, "stubs", "guarded_test.go"), `package stubs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errDenied = errors.New("denied")
+
+type users struct {
+	calls []string
+}
+
+func (u *users) Create(_ context.Context, name string) (int, error) {
+	u.calls = append(u.calls, "Create "+name)
+
+	return 1, nil
+}
+
+func (u *users) Get(id int) (string, error) {
+	u.calls = append(u.calls, fmt.Sprint("Get ", id))
+
+	return "user", nil
+}
+
+func (u *users) Reset() {
+	u.calls = append(u.calls, "Reset")
+}
+
+type policy struct {
+	deny     bool
+	requests []string
+}
+
+func (p *policy) Authorize(_ context.Context, method string, args ...any) error {
+	p.requests = append(p.requests, fmt.Sprint(method, args))
+
+	if p.deny {
+		return errDenied
+	}
+
+	return nil
+}
+
+func TestPolicyIsCheckedBeforeCall(t *testing.T) {
+	next := &users{}
+	pol := &policy{}
+	guarded := NewGuardedUsers(next, pol)
+
+	if id, err := guarded.Create(context.Background(), "john"); id != 1 || err != nil {
+		t.Fatalf("Create() = (%d, %v)", id, err)
+	}
+
+	if name, err := guarded.Get(2); name != "user" || err != nil {
+		t.Fatalf("Get() = (%q, %v)", name, err)
+	}
+
+	guarded.Reset()
+
+	wantRequests := fmt.Sprint([]string{"users.write[john]", "Users.Get[2]", "Users.Reset[]"})
+	if got := fmt.Sprint(pol.requests); got != wantRequests {
+		t.Errorf("policy requests = %s, want %s", got, wantRequests)
+	}
+
+	if got := fmt.Sprint(next.calls); got != fmt.Sprint([]string{"Create john", "Get 2", "Reset"}) {
+		t.Errorf("calls = %s", got)
+	}
+}
+
+func TestDeniedCallIsShortCircuited(t *testing.T) {
+	next := &users{}
+	guarded := NewGuardedUsers(next, &policy{deny: true})
+
+	if id, err := guarded.Create(context.Background(), "john"); id != 0 || !errors.Is(err, errDenied) {
+		t.Errorf("Create() = (%d, %v), want (0, %v)", id, err, errDenied)
+	}
+
+	if name, err := guarded.Get(2); name != "" || !errors.Is(err, errDenied) {
+		t.Errorf("Get() = (%q, %v), want (\"\", %v)", name, err, errDenied)
+	}
+
+	func() {
+		defer func() {
+			if message, _ := recover().(string); message != "GuardedUsers.Reset: denied" {
+				t.Errorf("Reset() panics with %q", message)
+			}
+		}()
+
+		guarded.Reset()
+	}()
+
+	if len(next.calls) != 0 {
+		t.Errorf("denied calls are delegated: %v", next.calls)
+	}
+}
+`)
+
+	assertCompiles(t, dir)
+}
+
+const unimplementedContracts = `package contracts
+
+import "context"
+
+type Users interface {
+	Get(ctx context.Context, id int) (string, error)
+	Delete(ctx context.Context, id int) error
+	Reset()
+}
+`
+
+func TestUnimplementedKindIsEmbeddable(t *testing.T) {
+	dir := newTestModuleWithContracts(t, unimplementedContracts)
+
+	generateKind(t, dir, st.KindUnimplemented, nil)
+
+	writeTestFile(t, filepath.Join(dir, "stubs", "embed_test.go"), `package stubs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/m/contracts"
+)
+
+// users implements only Get, other methods are taken from embedded type.
+type users struct {
+	UnimplementedUsers
+}
+
+var _ contracts.Users = users{}
+
+func (users) Get(context.Context, int) (string, error) {
+	return "user", nil
+}
+
+func TestUnimplementedMethodsReturnSentinelError(t *testing.T) {
+	var impl contracts.Users = users{}
+
+	if name, err := impl.Get(context.Background(), 1); name != "user" || err != nil {
+		t.Errorf("Get() = (%q, %v)", name, err)
+	}
+
+	err := impl.Delete(context.Background(), 1)
+	if !errors.Is(err, ErrUnimplementedUsers) {
+		t.Errorf("Delete() error = %v, want %v", err, ErrUnimplementedUsers)
+	}
+
+	if err.Error() != "Users method is not implemented: Users.Delete" {
+		t.Errorf("Delete() error = %q", err)
+	}
+
+	// methods without results must not panic
+	impl.Reset()
+
+	users{}.mustEmbedUnimplementedUsers()
+}
+`)
+
+	assertCompiles(t, dir)
+}
+
+const channelContracts = `package contracts
+
+import "context"
+
+type User struct {
+	Name string
+}
+
+type Events interface {
+	Watch(ctx context.Context, ch <-chan int, id int, name string) (<-chan *User, error)
+}
+`
+
+func TestBuiltinBodiesRenderCodeWithoutEscaping(t *testing.T) {
+	dir := newTestModuleWithContracts(t, channelContracts)
+
+	for _, body := range []string{st.MethodBodyNilReturns, st.MethodBodyDefaults, st.MethodBodyFake} {
+		command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+			var err error
+
+			params.MethodBody, err = st.FindMethodBody(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		err := command.Run(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Run() of %s body error = %v", body, err)
+		}
+
+		generated := readTestFile(t, filepath.Join(dir, "stubs", params.Kind.DefaultFilename))
+		if strings.Contains(generated, "&lt;") || strings.Contains(generated, "&amp;") || strings.Contains(generated, "&#") {
+			t.Errorf("%s body is escaped:\n%s", body, generated)
+		}
+
+		assertCompiles(t, dir)
+	}
+}
+
+func TestMethodBodyFileRendersCodeWithoutEscaping(t *testing.T) {
+	dir := newTestModuleWithContracts(t, channelContracts)
+
+	body := filepath.Join(dir, "body.tpl")
+	writeTestFile(t, body, `{{/* gostub:data-version 1 */}}{{ $typ := .Type }}
+{{- range .Method.Parameters.NonContext.List }}    if {{ .Name }} != {{ zeroOf . $typ.Package }} && 'a' < 'b' {
+        _ = {{ .Name }}
+    }
+{{ end }}    out := make(chan {{ typeOf (index .Method.Results.List 0).Type.Elem $typ.Package }}, 1)
+    out <- &contracts.User{Name: {{ quote "<&'>" }}}
+    close(out)
+
+    return out, nil`)
+
+	methodBody, err := st.FindMethodBody("file:" + body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generateKind(t, dir, st.KindStub, func(params *Params) {
+		params.MethodBody = methodBody
+	})
+
+	assertCompiles(t, dir)
+}
+
+const declaredOutOfSourceContracts = `package contracts
+
+import "example.com/m/models"
+
+type Users interface {
+	Get(id ID) (models.User, *models.User, models.Attrs, Tags, error)
+}
+`
+
+func TestDefaultsBodyOfTypesDeclaredOutOfSource(t *testing.T) {
+	dir := newTestModuleWithContracts(t, declaredOutOfSourceContracts)
+
+	writeTestFile(t, filepath.Join(dir, "contracts", "types.go"), `package contracts
+
+type ID int
+
+type Tags []string
+`)
+	writeTestFile(t, filepath.Join(dir, "models", "models.go"), `package models
+
+type User struct {
+	Name string
+}
+
+type Attrs map[string]string
+`)
+
+	command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+		var err error
+
+		params.MethodBody, err = st.FindMethodBody(st.MethodBodyDefaults)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	err := command.Run(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	assertCompiles(t, dir)
+
+	generated := readTestFile(t, filepath.Join(dir, "stubs", params.Kind.DefaultFilename))
+	for _, value := range []string{"models.User{}", "&models.User{}", "models.Attrs{}", "contracts.Tags{}"} {
+		if !strings.Contains(generated, value) {
+			t.Errorf("generated code doesn't contain %q:\n%s", value, generated)
+		}
+	}
+}
+
+const defaultsContracts = `package contracts
+
+import (
+	"context"
+	"time"
+)
+
+type User struct {
+	Name string
+}
+
+type Users interface {
+	List(ctx context.Context) ([]*User, map[string]User, <-chan User, *User, time.Time, context.Context, error)
+}
+`
+
+func TestDefaultsBodyReturnsUsableResults(t *testing.T) {
+	dir := newTestModuleWithContracts(t, defaultsContracts)
+
+	command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+		var err error
+
+		params.MethodBody, err = st.FindMethodBody(st.MethodBodyDefaults)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	err := command.Run(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "stubs", "defaults_test.go"), `package stubs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResultsAreUsable(t *testing.T) {
+	users, byName, events, user, createdAt, ctx, err := (&StubUsers{}).List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if users == nil || byName == nil {
+		t.Error("slices and maps must not be nil")
+	}
+
+	for range users {
+	}
+
+	for range events {
+	}
+
+	_ = user.Name
+
+	if !createdAt.IsZero() || ctx.Err() != nil {
+		t.Errorf("got time %v and context error %v", createdAt, ctx.Err())
+	}
+}
+`)
+
+	assertCompiles(t, dir)
+}
+
+func TestCtxAwareBodiesReturnErrorOfCancelledContext(t *testing.T) {
+	dir := newTestModule(t)
+
+	for out, body := range map[string]string{"nilreturns": st.MethodBodyNilReturns, "panics": st.MethodBodyPanic} {
+		command, params := newTestCommand(t, dir, out, func(params *Params) {
+			var err error
+
+			params.MethodBody, err = st.FindMethodBody(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params.CtxAware = true
+		})
+
+		err := command.Run(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Run() of %s body error = %v", body, err)
+		}
+	}
+
+	writeTestFile(t, filepath.Join(dir, "nilreturns", "ctx_test.go"), `package nilreturns
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := (&StubUsers{}).Get(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := (&StubUsers{}).Get(context.Background(), 1); !errors.Is(err, ErrStubNotImplemented) {
+		t.Errorf("Get() of active context error = %v, want error of body %v", err, ErrStubNotImplemented)
+	}
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "panics", "ctx_test.go"), `package panics
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := (&StubNotifier{}).Notify(ctx, "hello"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Notify() error = %v, want %v", err, context.Canceled)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Notify() of active context must run panic body")
+		}
+	}()
+
+	_ = (&StubNotifier{}).Notify(context.Background(), "hello")
+}
+`)
+
+	assertCompiles(t, dir)
+}
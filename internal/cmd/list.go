@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/artarts36/gostub/internal/golang"
+)
+
+// List prints interfaces of source and their methods.
+func (c *Command) List(ctx context.Context, params *Params) error {
+	slog.
+		With(slog.Any("params", params)).
+		InfoContext(ctx, "[command] listing")
+
+	src, err := os.ReadFile(params.Source)
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", params.Source, err)
+	}
+
+	sourceAbsPath, err := filepath.Abs(params.Source)
+	if err != nil {
+		return fmt.Errorf("failed to get absoulte source path for source %q: %w", params.Source, err)
+	}
+
+	parsedFile, err := golang.ParseInterfacesFromSource(golang.ParseInterfacesParams{
+		Source:         src,
+		SourcePath:     sourceAbsPath,
+		FilterNames:    params.Interfaces,
+		SourceGoModule: params.SourceGoModule,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to parse go file: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.stdout, "%s\n", params.Source)
+	if err != nil {
+		return err
+	}
+
+	for _, iface := range parsedFile.Interfaces {
+		_, err = fmt.Fprintf(c.stdout, "  %s\n", iface.Name.Value)
+		if err != nil {
+			return err
+		}
+
+		for _, method := range iface.Methods {
+			_, err = fmt.Fprintf(c.stdout, "    %s\n", method.Signature())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
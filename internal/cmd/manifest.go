@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/artarts36/gostub/internal/golang"
+	st "github.com/artarts36/gostub/internal/stub"
+)
+
+// ManifestFilename is name of manifest in root of target module.
+const ManifestFilename = ".gostub.lock.json"
+
+// ErrModified is returned when recorded files are modified by hand or removed.
+var ErrModified = errors.New("generated files are modified or missing")
+
+type FileStatus string
+
+const (
+	FileStatusOK       FileStatus = "ok"
+	FileStatusModified FileStatus = "modified"
+	FileStatusMissing  FileStatus = "missing"
+	FileStatusStale    FileStatus = "stale"
+)
+
+// Manifest is list of files written by gostub in module.
+type Manifest struct {
+	// Dir is root of module, paths of files are relative to it.
+	Dir string `json:"-"`
+
+	Files []*ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	// Path is slash-separated path relative to root of module.
+	Path       string   `json:"path"`
+	Source     string   `json:"source"`
+	Interfaces []string `json:"interfaces"`
+	Kind       string   `json:"kind"`
+	Template   string   `json:"template"`
+	Hash       string   `json:"hash"`
+	// Editable is set for per method files without header, which are expected to be edited.
+	Editable bool `json:"editable,omitempty"`
+	// Stale is set when last generation of source no longer produces file.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// LoadManifest reads manifest from root of module, returns empty manifest when it doesn't exist.
+func LoadManifest(moduleDir string) (*Manifest, error) {
+	manifest := &Manifest{
+		Dir:   moduleDir,
+		Files: make([]*ManifestFile, 0),
+	}
+
+	content, err := readExisting(filepath.Join(moduleDir, ManifestFilename))
+	if err != nil || content == nil {
+		return manifest, err
+	}
+
+	err = json.Unmarshal(content, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", ManifestFilename, err)
+	}
+
+	return manifest, nil
+}
+
+// Save writes manifest, files are sorted by path.
+func (m *Manifest) Save() error {
+	sort.Slice(m.Files, func(i, j int) bool {
+		return m.Files[i].Path < m.Files[j].Path
+	})
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", ManifestFilename, err)
+	}
+
+	filename := filepath.Join(m.Dir, ManifestFilename)
+
+	old, err := readExisting(filename)
+	if err != nil {
+		return err
+	}
+
+	plan := &Plan{}
+	plan.add(filename, old, append(content, '\n'))
+
+	return plan.Apply()
+}
+
+// AbsPath returns path of file in file system.
+func (m *Manifest) AbsPath(file *ManifestFile) string {
+	return filepath.Join(m.Dir, filepath.FromSlash(file.Path))
+}
+
+// Status compares file on disk with recorded hash.
+func (m *Manifest) Status(file *ManifestFile) (FileStatus, error) {
+	content, err := readExisting(m.AbsPath(file))
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case content == nil:
+		return FileStatusMissing, nil
+	case hashContent(content) != file.Hash:
+		return FileStatusModified, nil
+	case file.Stale:
+		return FileStatusStale, nil
+	}
+
+	return FileStatusOK, nil
+}
+
+// Remove removes files from manifest.
+func (m *Manifest) Remove(files []*ManifestFile) {
+	removed := map[*ManifestFile]bool{}
+	for _, file := range files {
+		removed[file] = true
+	}
+
+	kept := make([]*ManifestFile, 0, len(m.Files))
+	for _, file := range m.Files {
+		if !removed[file] {
+			kept = append(kept, file)
+		}
+	}
+
+	m.Files = kept
+}
+
+func (m *Manifest) find(relPath string) *ManifestFile {
+	for _, file := range m.Files {
+		if file.Path == relPath {
+			return file
+		}
+	}
+
+	return nil
+}
+
+func (m *Manifest) relPath(filename string) (string, error) {
+	rel, err := relativePath(m.Dir, filename)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Clean(filepath.ToSlash(rel)), nil
+}
+
+// record adds generated files of plan, files of plan are in order of stubs.
+// Recorded files of the same source, kind and directory, which are not generated anymore, are marked as stale,
+// when their interfaces were generated by this run or were removed from source.
+func (m *Manifest) record(plan *Plan, stubs []*st.Stub, params *Params) error {
+	source, err := m.relPath(params.Source)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.ReadFile(params.Source)
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", params.Source, err)
+	}
+
+	declared, err := golang.InterfaceDecls(params.Source, src)
+	if err != nil {
+		return err
+	}
+
+	generated := map[string]bool{}
+	dirs := map[string]bool{}
+
+	for i, file := range plan.Files {
+		relPath, relErr := m.relPath(file.Path)
+		if relErr != nil {
+			return relErr
+		}
+
+		dirs[path.Dir(relPath)] = true
+
+		if file.Skipped {
+			if m.find(relPath) != nil {
+				generated[relPath] = true
+			}
+
+			continue
+		}
+
+		generated[relPath] = true
+
+		entry := m.find(relPath)
+		if entry == nil {
+			entry = &ManifestFile{Path: relPath}
+			m.Files = append(m.Files, entry)
+		}
+
+		stub := stubs[i]
+
+		entry.Source = source
+		entry.Interfaces = file.Interfaces
+		entry.Kind = params.Kind.Name
+		entry.Template = stubTemplate(stub)
+		entry.Hash = hashContent(file.New)
+		entry.Editable = stub.PerMethod && params.EditablePerMethod
+		entry.Stale = false
+	}
+
+	for _, entry := range m.Files {
+		if entry.Source == source && entry.Kind == params.Kind.Name && dirs[path.Dir(entry.Path)] && !generated[entry.Path] &&
+			ownedByRun(entry, params.Interfaces, declared) {
+			entry.Stale = true
+		}
+	}
+
+	return nil
+}
+
+// ownedByRun reports whether file is produced by generation of filtered interfaces of source:
+// all interfaces are generated without filter, files of removed interfaces are owned by every run.
+func ownedByRun(entry *ManifestFile, filter []string, declared map[string]string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, name := range entry.Interfaces {
+		if _, ok := declared[name]; !ok || slices.Contains(filter, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// refresh updates hashes of recorded files, which were changed by gostub.
+func (m *Manifest) refresh(plan *Plan) error {
+	for _, file := range plan.Changes() {
+		relPath, err := m.relPath(file.Path)
+		if err != nil {
+			return err
+		}
+
+		if entry := m.find(relPath); entry != nil {
+			entry.Hash = hashContent(file.New)
+		}
+	}
+
+	return nil
+}
+
+func stubInterfaces(stub *st.Stub) []string {
+	interfaces := make([]string, 0, len(stub.Types))
+	for _, typ := range stub.Types {
+		interfaces = append(interfaces, typ.Interface.Name.Value)
+	}
+
+	return interfaces
+}
+
+func stubTemplate(stub *st.Stub) string {
+	switch {
+	case stub.GenMethods:
+		return stub.MethodTpl
+	case stub.GenTypes:
+		return stub.TypesTpl
+	}
+
+	return stub.ErrorsTpl
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManifestRecordKeepsFilesOfOtherInterfaces(t *testing.T) {
+	dir := newTestModule(t)
+
+	run := func(filename, iface string) {
+		t.Helper()
+
+		command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+			params.Filename = filename
+			params.Interfaces = []string{iface}
+		})
+
+		err := command.Run(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Run() of %s error = %v", filename, err)
+		}
+	}
+
+	// two jobs of the same source and out directory
+	run("users.go", "Users")
+	run("notifier.go", "Notifier")
+	run("users.go", "Users")
+
+	assertStale(t, dir, map[string]bool{
+		"stubs/users.go":    false,
+		"stubs/notifier.go": false,
+	})
+
+	// files of interface removed from source are stale
+	contracts := filepath.Join(dir, "contracts", "contracts.go")
+	writeTestFile(t, contracts, strings.Split(testContracts, "type Notifier")[0])
+
+	run("users.go", "Users")
+
+	assertStale(t, dir, map[string]bool{
+		"stubs/users.go":    false,
+		"stubs/notifier.go": true,
+	})
+}
+
+func TestManifestRecordMarksNotGeneratedFilesStale(t *testing.T) {
+	dir := newTestModule(t)
+
+	for _, perType := range []bool{true, false} {
+		command, params := newTestCommand(t, dir, "stubs", func(params *Params) {
+			params.TypePerFile = perType
+		})
+
+		err := command.Run(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	assertStale(t, dir, map[string]bool{
+		"stubs/stubs.go":         false,
+		"stubs/users_stub.go":    true,
+		"stubs/notifier_stub.go": true,
+	})
+}
+
+func TestManifestStatus(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(dir, "ok.go"), "package p\n")
+	writeTestFile(t, filepath.Join(dir, "modified.go"), "package p\n\n// edited\n")
+	writeTestFile(t, filepath.Join(dir, "stale.go"), "package p\n")
+
+	hash := hashContent([]byte("package p\n"))
+
+	manifest := &Manifest{Dir: dir}
+
+	cases := []struct {
+		file *ManifestFile
+		want FileStatus
+	}{
+		{file: &ManifestFile{Path: "ok.go", Hash: hash}, want: FileStatusOK},
+		{file: &ManifestFile{Path: "modified.go", Hash: hash}, want: FileStatusModified},
+		{file: &ManifestFile{Path: "missing.go", Hash: hash}, want: FileStatusMissing},
+		{file: &ManifestFile{Path: "stale.go", Hash: hash, Stale: true}, want: FileStatusStale},
+	}
+
+	for _, c := range cases {
+		got, err := manifest.Status(c.file)
+		if err != nil {
+			t.Fatalf("Status(%s) error = %v", c.file.Path, err)
+		}
+
+		if got != c.want {
+			t.Errorf("Status(%s) = %s, want %s", c.file.Path, got, c.want)
+		}
+	}
+}
+
+// assertStale checks stale flags of recorded files of manifest of module.
+func assertStale(t *testing.T, dir string, want map[string]bool) {
+	t.Helper()
+
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, stale := range want {
+		file := manifest.find(path)
+		if file == nil {
+			t.Errorf("file %s isn't recorded", path)
+
+			continue
+		}
+
+		if file.Stale != stale {
+			t.Errorf("file %s stale = %v, want %v", path, file.Stale, stale)
+		}
+
+		if _, statErr := os.Stat(manifest.AbsPath(file)); statErr != nil {
+			t.Errorf("recorded file %s doesn't exist: %v", path, statErr)
+		}
+	}
+}
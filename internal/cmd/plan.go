@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const generatedFileMode os.FileMode = 0644
+
+type FileAction string
+
+const (
+	FileActionCreate FileAction = "create"
+	FileActionChange FileAction = "change"
+	FileActionKeep   FileAction = "keep"
+)
+
+// PlannedFile is file rendered in memory, which isn't written yet.
+type PlannedFile struct {
+	Path   string
+	Action FileAction
+
+	// Old is content of file on disk, nil when file doesn't exist.
+	Old []byte
+	// New is content of file after generation, equals Old when file is kept.
+	New []byte
+	// Skipped is set when existing file is kept without generation.
+	Skipped bool
+	// Interfaces are names of interfaces, which code file contains.
+	Interfaces []string
+
+	// oldMode is mode of file on disk, used to restore file on rollback.
+	oldMode os.FileMode
+}
+
+// Plan is set of files, which generation produces.
+type Plan struct {
+	Files []*PlannedFile
+}
+
+func (p *Plan) add(path string, old, new []byte) {
+	file := &PlannedFile{
+		Path: path,
+		Old:  old,
+		New:  new,
+	}
+
+	switch {
+	case new == nil:
+		file.Action = FileActionKeep
+		file.New = old
+		file.Skipped = true
+	case old == nil:
+		file.Action = FileActionCreate
+	case bytes.Equal(old, new):
+		file.Action = FileActionKeep
+	case bytes.Equal(withoutVersion(old), withoutVersion(new)):
+		// file generated by another version of gostub isn't rewritten only to update header
+		file.Action = FileActionKeep
+		file.New = old
+	default:
+		file.Action = FileActionChange
+	}
+
+	p.Files = append(p.Files, file)
+}
+
+// Changes returns files, which must be created or changed.
+func (p *Plan) Changes() []*PlannedFile {
+	changes := make([]*PlannedFile, 0, len(p.Files))
+
+	for _, file := range p.Files {
+		if file.Action != FileActionKeep {
+			changes = append(changes, file)
+		}
+	}
+
+	return changes
+}
+
+// Print writes list of planned files with their actions.
+func (p *Plan) Print(w io.Writer) error {
+	for _, file := range p.Files {
+		_, err := fmt.Fprintf(w, "%-6s %s\n", file.Action, file.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrintDiff writes unified diff of planned changes.
+func (p *Plan) PrintDiff(w io.Writer) error {
+	for _, file := range p.Changes() {
+		path := filepath.ToSlash(filepath.Clean(file.Path))
+
+		oldName := "a/" + path
+		if file.Old == nil {
+			oldName = os.DevNull
+		}
+
+		_, err := io.WriteString(w, unifiedDiff(oldName, "b/"+path, file.Old, file.New))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readExisting returns content of file, nil when file doesn't exist.
+func readExisting(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read existing file %q: %w", path, err)
+	}
+
+	return content, nil
+}
+
+// Apply writes planned changes: every file is written to temp file first and then renamed to target.
+// When any file can't be written, already renamed files are restored and temp files are removed.
+func (p *Plan) Apply() error {
+	changes := p.Changes()
+
+	temps := make([]string, 0, len(changes))
+
+	removeTemps := func() {
+		for _, temp := range temps {
+			_ = os.Remove(temp)
+		}
+	}
+
+	for _, file := range changes {
+		file.oldMode = generatedFileMode
+
+		if file.Old == nil {
+			continue
+		}
+
+		info, statErr := os.Stat(file.Path)
+		if statErr != nil {
+			return fmt.Errorf("failed to stat file %q: %w", file.Path, statErr)
+		}
+
+		file.oldMode = info.Mode().Perm()
+	}
+
+	dirs, err := createDirs(changes)
+	if err != nil {
+		return err
+	}
+
+	removeDirs := func() {
+		for i := len(dirs) - 1; i >= 0; i-- {
+			_ = os.Remove(dirs[i])
+		}
+	}
+
+	for _, file := range changes {
+		temp, tempErr := writeTemp(file.Path, file.New, generatedFileMode)
+		if tempErr != nil {
+			removeTemps()
+			removeDirs()
+
+			return tempErr
+		}
+
+		temps = append(temps, temp)
+	}
+
+	for i, file := range changes {
+		err := os.Rename(temps[i], file.Path)
+		if err != nil {
+			removeTemps()
+			rollback(changes[:i])
+			removeDirs()
+
+			return fmt.Errorf("failed to write file %q: %w", file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// createDirs creates missing directories of files, returns created directories from parent to child.
+func createDirs(files []*PlannedFile) ([]string, error) {
+	created := make([]string, 0)
+
+	for _, file := range files {
+		missing := make([]string, 0)
+
+		for dir := filepath.Dir(file.Path); ; dir = filepath.Dir(dir) {
+			if _, err := os.Stat(dir); err == nil {
+				break
+			}
+
+			missing = append(missing, dir)
+
+			if dir == filepath.Dir(dir) {
+				break
+			}
+		}
+
+		for i := len(missing) - 1; i >= 0; i-- {
+			err := os.Mkdir(missing[i], 0755)
+			if err != nil {
+				for j := len(created) - 1; j >= 0; j-- {
+					_ = os.Remove(created[j])
+				}
+
+				return nil, fmt.Errorf("failed to create directory %q: %w", missing[i], err)
+			}
+
+			created = append(created, missing[i])
+		}
+	}
+
+	return created, nil
+}
+
+func writeTemp(path string, content []byte, mode os.FileMode) (string, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file for %q: %w", path, err)
+	}
+
+	_, err = file.Write(content)
+	if err == nil {
+		err = file.Chmod(mode)
+	}
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(file.Name())
+
+		return "", fmt.Errorf("failed to write temp file for %q: %w", path, err)
+	}
+
+	return file.Name(), nil
+}
+
+// rollback restores content and mode of files, which were already written.
+func rollback(files []*PlannedFile) {
+	for _, file := range files {
+		if file.Old == nil {
+			_ = os.Remove(file.Path)
+
+			continue
+		}
+
+		temp, err := writeTemp(file.Path, file.Old, file.oldMode)
+		if err != nil {
+			continue
+		}
+
+		if os.Rename(temp, file.Path) != nil {
+			_ = os.Remove(temp)
+		}
+	}
+}
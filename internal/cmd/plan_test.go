@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanAdd(t *testing.T) {
+	cases := []struct {
+		name    string
+		old     []byte
+		new     []byte
+		action  FileAction
+		skipped bool
+	}{
+		{name: "create", new: []byte("a"), action: FileActionCreate},
+		{name: "change", old: []byte("a"), new: []byte("b"), action: FileActionChange},
+		{name: "keep equal", old: []byte("a"), new: []byte("a"), action: FileActionKeep},
+		{name: "skip", old: []byte("a"), action: FileActionKeep, skipped: true},
+		{
+			name:   "keep generated by another version",
+			old:    []byte("// Code generated by gostub v1.0.0; DO NOT EDIT.\n\npackage a\n"),
+			new:    []byte("// Code generated by gostub v1.1.0; DO NOT EDIT.\n\npackage a\n"),
+			action: FileActionKeep,
+		},
+		{
+			name:   "change generated by another version",
+			old:    []byte("// Code generated by gostub v1.0.0; DO NOT EDIT.\n\npackage a\n"),
+			new:    []byte("// Code generated by gostub v1.1.0; DO NOT EDIT.\n\npackage b\n"),
+			action: FileActionChange,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			plan := &Plan{}
+			plan.add("f.go", c.old, c.new)
+
+			file := plan.Files[0]
+			if file.Action != c.action || file.Skipped != c.skipped {
+				t.Errorf("add() = (%s, skipped: %v), want (%s, skipped: %v)", file.Action, file.Skipped, c.action, c.skipped)
+			}
+
+			if c.action == FileActionKeep && !bytes.Equal(file.New, c.old) {
+				t.Errorf("kept file must have old content, got %q", file.New)
+			}
+		})
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	dir := t.TempDir()
+
+	changed := filepath.Join(dir, "changed.go")
+	writeTestFile(t, changed, "old")
+
+	created := filepath.Join(dir, "sub", "dir", "created.go")
+
+	plan := &Plan{}
+	plan.add(changed, []byte("old"), []byte("new"))
+	plan.add(created, nil, []byte("created"))
+
+	err := plan.Apply()
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	for path, content := range map[string]string{changed: "new", created: "created"} {
+		if got := readTestFile(t, path); got != content {
+			t.Errorf("content of %s = %q, want %q", path, got, content)
+		}
+
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			t.Fatal(statErr)
+		}
+
+		if info.Mode().Perm() != generatedFileMode {
+			t.Errorf("mode of %s = %s, want %s", path, info.Mode().Perm(), generatedFileMode)
+		}
+	}
+
+	assertNoTempFiles(t, dir)
+}
+
+func TestPlanApplyRollback(t *testing.T) {
+	dir := t.TempDir()
+
+	changed := filepath.Join(dir, "changed.go")
+	writeTestFile(t, changed, "old")
+
+	err := os.Chmod(changed, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := filepath.Join(dir, "new", "created.go")
+
+	// file can't be renamed over non-empty directory
+	broken := filepath.Join(dir, "broken.go")
+	writeTestFile(t, filepath.Join(broken, "file"), "")
+
+	plan := &Plan{}
+	plan.add(changed, []byte("old"), []byte("new"))
+	plan.add(created, nil, []byte("created"))
+	plan.add(broken, nil, []byte("broken"))
+
+	err = plan.Apply()
+	if err == nil {
+		t.Fatal("Apply() must fail")
+	}
+
+	if got := readTestFile(t, changed); got != "old" {
+		t.Errorf("content of changed file = %q, want restored %q", got, "old")
+	}
+
+	info, err := os.Stat(changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode of changed file = %s, want restored %s", info.Mode().Perm(), os.FileMode(0600))
+	}
+
+	if _, statErr := os.Stat(filepath.Dir(created)); !os.IsNotExist(statErr) {
+		t.Errorf("created directory must be removed, stat error = %v", statErr)
+	}
+
+	assertNoTempFiles(t, dir)
+}
+
+func TestPlanPrintDiff(t *testing.T) {
+	plan := &Plan{}
+	plan.add("./out/a.go", []byte("package a\n\nfunc A() {}\n"), []byte("package a\n\nfunc A() {}\n\nfunc B() {}\n"))
+	plan.add("./out/b.go", nil, []byte("package b\n"))
+	plan.add("./out/c.go", []byte("package c\n"), []byte("package c\n"))
+
+	out := &bytes.Buffer{}
+
+	err := plan.PrintDiff(out)
+	if err != nil {
+		t.Fatalf("PrintDiff() error = %v", err)
+	}
+
+	want := `--- a/out/a.go
++++ b/out/a.go
+@@ -1,3 +1,5 @@
+ package a
+ 
+ func A() {}
++
++func B() {}
+--- ` + os.DevNull + `
++++ b/out/b.go
+@@ -0,0 +1,1 @@
++package b
+`
+
+	if out.String() != want {
+		t.Errorf("PrintDiff() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	_ = filepath.WalkDir(dir, func(path string, _ os.DirEntry, _ error) error {
+		if strings.HasSuffix(path, ".tmp") {
+			t.Errorf("temp file %s is left", path)
+		}
+
+		return nil
+	})
+}
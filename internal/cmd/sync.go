@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/artarts36/goimports"
+	"github.com/artarts36/gostub/internal/golang"
+)
+
+// ErrOrphaned is returned by sync when existing files have methods, which are no longer part of interfaces.
+var ErrOrphaned = errors.New("methods are not part of interfaces")
+
+// Sync fixes signatures of methods in existing files, which differ from interfaces,
+// and reports methods which are no longer part of interfaces.
+// Returns ErrOrphaned after fixing signatures, when such methods are found.
+func (c *Command) Sync(ctx context.Context, params *Params) error {
+	slog.
+		With(slog.Any("params", params)).
+		InfoContext(ctx, "[command] syncing")
+
+	stubs, err := c.prepareStubs(params)
+	if err != nil {
+		return err
+	}
+
+	expected := map[string]*golang.MethodSignature{}
+	types := map[string]bool{}
+	candidates := make([]*goimports.ImportGroups, 0)
+
+	for _, stub := range stubs {
+		if !stub.GenMethods {
+			continue
+		}
+
+		for _, typ := range stub.Types {
+			types[typ.Name] = true
+		}
+
+		code, renderErr := c.render(stub.Filename, stub, params)
+		if renderErr != nil {
+			return renderErr
+		}
+
+		signatures, sigErr := golang.ExtractMethodSignatures(stub.Filename, code)
+		if sigErr != nil {
+			return fmt.Errorf("failed to extract signatures from generated code: %w", sigErr)
+		}
+
+		for key, signature := range signatures {
+			expected[key] = signature
+		}
+
+		candidates = append(candidates, stub.ImportCandidates()...)
+	}
+
+	dir := params.Out
+	if dir == "" {
+		dir = "."
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return fmt.Errorf("failed to list files in %q: %w", dir, err)
+	}
+
+	sort.Strings(files)
+
+	orphaned := make([]string, 0)
+	plan := &Plan{}
+
+	for _, filename := range files {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		src, readErr := os.ReadFile(filename)
+		if readErr != nil {
+			return fmt.Errorf("failed to read %q: %w", filename, readErr)
+		}
+
+		result, syncErr := golang.SyncSource(golang.SyncParams{
+			Filename:   filename,
+			Source:     src,
+			Expected:   expected,
+			Types:      types,
+			Module:     params.TargetGoModule.Module.Mod.Path,
+			Candidates: candidates,
+
+			PackageNames: params.packageNames(),
+		})
+		if syncErr != nil {
+			return fmt.Errorf("failed to sync %q: %w", filename, syncErr)
+		}
+
+		for _, key := range result.Orphaned {
+			orphaned = append(orphaned, fmt.Sprintf("%s: %s", filename, key))
+		}
+
+		if result.Source == nil {
+			continue
+		}
+
+		slog.InfoContext(ctx, "[command] fixing signatures", slog.String("file", filename), slog.Any("methods", result.Fixed))
+
+		plan.add(filename, src, result.Source)
+	}
+
+	err = plan.Apply()
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Changes()) > 0 {
+		manifest, manifestErr := LoadManifest(filepath.Dir(params.TargetGoModule.Path))
+		if manifestErr != nil {
+			return manifestErr
+		}
+
+		if len(manifest.Files) > 0 {
+			err = manifest.refresh(plan)
+			if err != nil {
+				return err
+			}
+
+			err = manifest.Save()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(orphaned) == 0 {
+		return nil
+	}
+
+	out := strings.Builder{}
+	for _, method := range orphaned {
+		out.WriteString(fmt.Sprintf("%s is not part of interfaces, remove it manually\n", method))
+	}
+
+	_, err = c.stdout.Write([]byte(out.String()))
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %d methods", ErrOrphaned, len(orphaned))
+}
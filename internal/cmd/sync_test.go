@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSyncReportsOrphanedMethods(t *testing.T) {
+	dir := newTestModule(t)
+
+	command, params := newTestCommand(t, dir, "stubs", nil)
+
+	err := command.Run(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "contracts", "contracts.go"), `package contracts
+
+import "context"
+
+type Users interface {
+	Get(ctx context.Context, id string) (string, error)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, message string) error
+}
+`)
+
+	command, params = newTestCommand(t, dir, "stubs", nil)
+
+	err = command.Sync(context.Background(), params)
+	if !errors.Is(err, ErrOrphaned) {
+		t.Fatalf("Sync() error = %v, want %v", err, ErrOrphaned)
+	}
+
+	path := filepath.Join(dir, "stubs", "stubs.go")
+
+	out := command.stdout.(*bytes.Buffer).String()
+	want := path + ": StubUsers.Delete is not part of interfaces, remove it manually\n"
+
+	if out != want {
+		t.Errorf("Sync() printed %q, want %q", out, want)
+	}
+
+	if generated := readTestFile(t, path); !strings.Contains(generated, "id string") {
+		t.Errorf("signature of Get must be fixed with orphaned methods:\n%s", generated)
+	}
+}
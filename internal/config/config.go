@@ -0,0 +1,158 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultFilename = "gostub.yaml"
+
+const (
+	ModeCommon        = "common"
+	ModePerType       = "per-type"
+	ModePerMethod     = "per-method"
+	ModePerTypeMethod = "per-type-method"
+)
+
+type Config struct {
+	Jobs []*Job `yaml:"jobs"`
+}
+
+// Job is one generation, fields are equal to CLI options.
+type Job struct {
+	Name string `yaml:"name"`
+
+	Source     string   `yaml:"source"`
+	Interfaces []string `yaml:"interfaces"`
+
+	Kind       string `yaml:"kind"`
+	Mode       string `yaml:"mode"`
+	MethodBody string `yaml:"method_body"`
+	CtxAware   bool   `yaml:"ctx_aware"`
+
+	Filename          string `yaml:"filename"`
+	PerMethodFilename string `yaml:"per_method_filename"`
+	PerTypeFilename   string `yaml:"per_type_filename"`
+	TypeName          string `yaml:"type_name"`
+
+	Out       string `yaml:"out"`
+	Package   string `yaml:"package"`
+	Templates string `yaml:"templates"`
+
+	SkipExists        bool `yaml:"skip_exists"`
+	Update            bool `yaml:"update"`
+	EditablePerMethod bool `yaml:"editable_per_method"`
+}
+
+func Load(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := &Config{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %q: %w", path, err)
+	}
+
+	for i, job := range cfg.Jobs {
+		err = job.validate()
+		if err != nil {
+			return nil, fmt.Errorf("job #%d %s: %w", i+1, job.Name, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Title returns name of job or its source.
+func (j *Job) Title() string {
+	if j.Name != "" {
+		return j.Name
+	}
+
+	return j.Source
+}
+
+// Opts returns CLI options of job.
+func (j *Job) Opts() map[string]string {
+	opts := map[string]string{}
+
+	values := map[string]string{
+		"kind":                j.Kind,
+		"method-body":         j.MethodBody,
+		"filename":            j.Filename,
+		"per-method-filename": j.PerMethodFilename,
+		"per-type-filename":   j.PerTypeFilename,
+		"type-name":           j.TypeName,
+		"out":                 j.Out,
+		"package":             j.Package,
+		"templates":           j.Templates,
+		"interfaces":          strings.Join(j.Interfaces, ","),
+	}
+
+	for name, value := range values {
+		if value != "" {
+			opts[name] = value
+		}
+	}
+
+	flags := map[string]bool{
+		"ctx-aware":           j.CtxAware,
+		"skip-exists":         j.SkipExists,
+		"update":              j.Update,
+		"editable-per-method": j.EditablePerMethod,
+		"per-type":            j.Mode == ModePerType || j.Mode == ModePerTypeMethod,
+		"per-method":          j.Mode == ModePerMethod || j.Mode == ModePerTypeMethod,
+	}
+
+	for name, enabled := range flags {
+		if enabled {
+			opts[name] = ""
+		}
+	}
+
+	return opts
+}
+
+func (j *Job) validate() error {
+	if j.Source == "" {
+		return errors.New("source must be set")
+	}
+
+	switch j.Mode {
+	case "", ModeCommon, ModePerType, ModePerMethod, ModePerTypeMethod:
+	default:
+		return fmt.Errorf(
+			"unknown mode %q, available: %s",
+			j.Mode,
+			strings.Join([]string{ModeCommon, ModePerType, ModePerMethod, ModePerTypeMethod}, ", "),
+		)
+	}
+
+	return nil
+}
+
+// Starter is content of config created by "gostub init".
+const Starter = `jobs:
+  - name: stubs
+    # path to .go file with interfaces, relative to config
+    source: ./contracts/contracts.go
+    # interfaces: [Service]
+    # kind: stub
+    # mode: common, per-type, per-method or per-type-method
+    mode: common
+    method_body: panic
+    out: ./stubs
+    package: stubs
+`
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), DefaultFilename)
+
+	err := os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `jobs:
+  - name: stubs
+    source: ./contracts.go
+    interfaces: [Users, Notifier]
+    mode: per-type-method
+    method_body: panic
+    out: ./stubs
+    update: true
+  - source: ./feed.go
+`))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.Jobs) != 2 {
+		t.Fatalf("Load() returned %d jobs, want 2", len(cfg.Jobs))
+	}
+
+	want := map[string]string{
+		"interfaces":  "Users,Notifier",
+		"method-body": "panic",
+		"out":         "./stubs",
+		"update":      "",
+		"per-type":    "",
+		"per-method":  "",
+	}
+
+	if opts := cfg.Jobs[0].Opts(); !reflect.DeepEqual(opts, want) {
+		t.Errorf("Opts() = %v, want %v", opts, want)
+	}
+
+	if cfg.Jobs[0].Title() != "stubs" || cfg.Jobs[1].Title() != "./feed.go" {
+		t.Errorf("Title() = %q, %q, want name or source", cfg.Jobs[0].Title(), cfg.Jobs[1].Title())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unknown field",
+			content: "jobs:\n  - source: ./a.go\n    per_type: true\n",
+			wantErr: "field per_type not found",
+		},
+		{
+			name:    "unknown root field",
+			content: "job:\n  - source: ./a.go\n",
+			wantErr: "field job not found",
+		},
+		{
+			name:    "without source",
+			content: "jobs:\n  - name: stubs\n",
+			wantErr: "job #1 stubs: source must be set",
+		},
+		{
+			name:    "unknown mode",
+			content: "jobs:\n  - source: ./a.go\n  - source: ./b.go\n    mode: per-file\n",
+			wantErr: `job #2 : unknown mode "per-file"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, c.content))
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestStarterIsValid(t *testing.T) {
+	cfg, err := Load(writeConfig(t, Starter))
+	if err != nil {
+		t.Fatalf("Load() of starter error = %v", err)
+	}
+
+	if len(cfg.Jobs) != 1 || cfg.Jobs[0].Source == "" {
+		t.Errorf("starter config must contain job with source, got %+v", cfg.Jobs)
+	}
+}
@@ -0,0 +1,41 @@
+package golang
+
+import (
+	"go/ast"
+	"strings"
+)
+
+const directivePrefix = "//gostub:"
+
+type Directives map[string]string
+
+func parseDirectives(groups ...*ast.CommentGroup) Directives {
+	directives := Directives{}
+
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+
+		for _, comment := range group.List {
+			if !strings.HasPrefix(comment.Text, directivePrefix) {
+				continue
+			}
+
+			name, value, _ := strings.Cut(strings.TrimPrefix(comment.Text, directivePrefix), " ")
+			directives[name] = strings.TrimSpace(value)
+		}
+	}
+
+	return directives
+}
+
+func (d Directives) Get(name string) string {
+	return d[name]
+}
+
+func (d Directives) Has(name string) bool {
+	_, ok := d[name]
+
+	return ok
+}
@@ -0,0 +1,224 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/artarts36/gomodfinder"
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	fakeMaxDepth      = 3
+	fakeMaxNumber     = 100
+	fakeSliceElements = 2
+)
+
+type fakeGenerator struct {
+	target *gomodfinder.Package
+	source *gomodfinder.Package
+}
+
+func (t *GoParameterType) FakeFor(pkg *gomodfinder.Package, method string, index int) string {
+	if t.Kind == TypeKindContext || t.Resolved == nil {
+		return t.DefaultFor(pkg)
+	}
+
+	gen := &fakeGenerator{
+		target: pkg,
+		source: t.Package,
+	}
+
+	value, ok := gen.value(t.Resolved, fmt.Sprintf("%s.%d", method, index), strcase.ToKebab(method), 0, true)
+	if !ok {
+		return t.DefaultFor(pkg)
+	}
+
+	return value
+}
+
+func (g *fakeGenerator) value(typ types.Type, seed, hint string, depth int, imported bool) (string, bool) { //nolint:cyclop // switch by type kind
+	if depth > fakeMaxDepth {
+		return "", false
+	}
+
+	switch t := typ.(type) {
+	case *types.Basic:
+		return g.basic(t, seed, hint)
+	case *types.Named:
+		return g.named(t, seed, hint, depth, imported)
+	case *types.Pointer:
+		elem, ok := g.typeName(t.Elem(), imported)
+		if !ok {
+			return "", false
+		}
+
+		value, ok := g.value(t.Elem(), seed, hint, depth, imported)
+		if !ok {
+			return "", false
+		}
+
+		// address can be taken only of composite literal, e.g. not of time.Date(...)
+		if isCompositeLit(value) {
+			return "&" + value, true
+		}
+
+		return fmt.Sprintf("func() *%s { v := %s; return &v }()", elem, value), true
+	case *types.Slice:
+		name, ok := g.typeName(t, imported)
+		if !ok {
+			return "", false
+		}
+
+		elems := make([]string, 0, fakeSliceElements)
+		for i := 0; i < fakeSliceElements; i++ {
+			elem, elemOk := g.value(t.Elem(), fmt.Sprintf("%s[%d]", seed, i), hint, depth+1, imported)
+			if !elemOk {
+				return name + "{}", true
+			}
+
+			elems = append(elems, elem)
+		}
+
+		return fmt.Sprintf("%s{%s}", name, strings.Join(elems, ", ")), true
+	case *types.Map:
+		name, ok := g.typeName(t, imported)
+		if !ok {
+			return "", false
+		}
+
+		key, keyOk := g.value(t.Key(), seed+".key", hint, depth+1, imported)
+		val, valOk := g.value(t.Elem(), seed+".value", hint, depth+1, imported)
+		if !keyOk || !valOk {
+			return name + "{}", true
+		}
+
+		return fmt.Sprintf("%s{%s: %s}", name, key, val), true
+	}
+
+	return "", false
+}
+
+func isCompositeLit(value string) bool {
+	expr, err := parser.ParseExpr(value)
+	if err != nil {
+		return false
+	}
+
+	_, ok := expr.(*ast.CompositeLit)
+
+	return ok
+}
+
+func (g *fakeGenerator) named(t *types.Named, seed, hint string, depth int, imported bool) (string, bool) {
+	name, ok := g.typeName(t, imported)
+	if !ok {
+		return "", false
+	}
+
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		value, valueOk := g.basic(underlying, seed, hint)
+		if !valueOk {
+			return "", false
+		}
+
+		return fmt.Sprintf("%s(%s)", name, value), true
+	case *types.Struct:
+		if name == "time.Time" {
+			return fmt.Sprintf("time.Date(2024, 1, %d, 0, 0, 0, 0, time.UTC)", g.number(seed)%28+1), true
+		}
+
+		return g.structValue(name, t, underlying, seed, depth), true
+	case *types.Slice, *types.Map:
+		value, valueOk := g.value(underlying, seed, hint, depth, imported)
+		if !valueOk {
+			return name + "{}", true
+		}
+
+		return name + strings.TrimPrefix(value, types.TypeString(underlying, g.qualifier)), true
+	}
+
+	return "", false
+}
+
+func (g *fakeGenerator) structValue(name string, named *types.Named, st *types.Struct, seed string, depth int) string {
+	samePackage := named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == g.target.FullName()
+
+	fields := make([]string, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if field.Embedded() || (!field.Exported() && !samePackage) {
+			continue
+		}
+
+		value, ok := g.value(field.Type(), seed+"."+field.Name(), strcase.ToKebab(field.Name()), depth+1, false)
+		if !ok {
+			continue
+		}
+
+		fields = append(fields, fmt.Sprintf("%s: %s", field.Name(), value))
+	}
+
+	return fmt.Sprintf("%s{%s}", name, strings.Join(fields, ", "))
+}
+
+func (g *fakeGenerator) basic(t *types.Basic, seed, hint string) (string, bool) {
+	n := g.number(seed)
+
+	switch {
+	case t.Info()&types.IsString != 0:
+		switch {
+		case strings.Contains(hint, "email"):
+			return strconv.Quote(fmt.Sprintf("user%d@example.com", n)), true
+		case strings.Contains(hint, "url"):
+			return strconv.Quote(fmt.Sprintf("https://example.com/%s/%d", hint, n)), true
+		}
+
+		return strconv.Quote(fmt.Sprintf("%s-%d", hint, n)), true
+	case t.Info()&types.IsBoolean != 0:
+		return strconv.FormatBool(n%2 == 0), true
+	case t.Info()&types.IsFloat != 0:
+		return fmt.Sprintf("%d.5", n), true
+	case t.Info()&types.IsInteger != 0:
+		return strconv.FormatUint(n, 10), true
+	}
+
+	return "", false
+}
+
+func (g *fakeGenerator) number(seed string) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(seed))
+
+	return hash.Sum64()%fakeMaxNumber + 1
+}
+
+// typeName returns type name in target package. Types of packages which are not imported
+// by generated file can be named only inside result types.
+func (g *fakeGenerator) typeName(typ types.Type, imported bool) (string, bool) {
+	nameable := true
+
+	name := types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg.Path() != g.source.FullName() && !imported {
+			nameable = false
+		}
+
+		return g.qualifier(pkg)
+	})
+
+	return name, nameable
+}
+
+func (g *fakeGenerator) qualifier(pkg *types.Package) string {
+	if pkg.Path() == g.target.FullName() {
+		return ""
+	}
+
+	return pkg.Name()
+}
@@ -0,0 +1,70 @@
+package golang
+
+import (
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/artarts36/gomodfinder"
+)
+
+func TestFakeValueOfPointer(t *testing.T) {
+	timePkg := types.NewPackage("time", "time")
+	timeType := types.NewNamed(types.NewTypeName(token.NoPos, timePkg, "Time", nil), types.NewStruct(nil, nil), nil)
+
+	srcPkg := types.NewPackage("example.com/m/contracts", "contracts")
+	userType := types.NewNamed(
+		types.NewTypeName(token.NoPos, srcPkg, "User", nil),
+		types.NewStruct([]*types.Var{types.NewField(token.NoPos, srcPkg, "ID", types.Typ[types.Int], false)}, nil),
+		nil,
+	)
+
+	gen := &fakeGenerator{
+		target: &gomodfinder.Package{Name: "stubs", Path: "example.com/m/stubs"},
+		source: &gomodfinder.Package{Name: "contracts", Path: "example.com/m/contracts"},
+	}
+
+	cases := []struct {
+		name string
+		typ  types.Type
+	}{
+		{name: "pointer to time", typ: types.NewPointer(timeType)},
+		{name: "pointer to struct", typ: types.NewPointer(userType)},
+		{name: "pointer to int", typ: types.NewPointer(types.Typ[types.Int])},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			value, ok := gen.value(c.typ, "Get.0", "get", 0, true)
+			if !ok {
+				t.Fatalf("value() of %s isn't generated", c.typ)
+			}
+
+			if _, err := parser.ParseExpr(value); err != nil {
+				t.Fatalf("value() = %q is invalid expression: %v", value, err)
+			}
+
+			// address of call expression isn't valid: &time.Date(...)
+			if value[0] == '&' && !isCompositeLit(value[1:]) {
+				t.Errorf("value() = %q takes address of non composite literal", value)
+			}
+		})
+	}
+}
+
+func TestIsCompositeLit(t *testing.T) {
+	cases := map[string]bool{
+		"User{ID: 1}":                true,
+		"contracts.User{}":           true,
+		"time.Date(2024, 1, 1, 0)":   false,
+		"1":                          false,
+		"func() *int { return nil }": false,
+	}
+
+	for value, want := range cases {
+		if got := isCompositeLit(value); got != want {
+			t.Errorf("isCompositeLit(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package golang
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/artarts36/goimports"
+)
+
+// stdImportCandidates is std packages which can be used by templates without declaring imports.
+var stdImportCandidates = []string{
+	"bytes",
+	"context",
+	"errors",
+	"fmt",
+	"io",
+	"sort",
+	"strconv",
+	"strings",
+	"sync",
+	"time",
+}
+
+type FormatParams struct {
+	Filename string
+	Source   []byte
+
+	// Module is path of target module, used for grouping imports.
+	Module string
+	// Candidates is imports which can be added when generated code uses them without import.
+	Candidates []*goimports.ImportGroups
+	// PackageNames resolves names of imported packages, imports with unresolved names are kept.
+	PackageNames *PackageNames
+	// Prunable is names of imports, which can be pruned when unused. All imports can be pruned when it is nil,
+	// no imports are pruned when it is empty: code of users keeps its imports.
+	Prunable map[string]bool
+}
+
+// FormatError points to place of generated code, which can't be parsed.
+type FormatError struct {
+	Filename string
+	Line     int
+	Column   int
+	Message  string
+	Code     string
+}
+
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s\n%6d | %s", e.Filename, e.Line, e.Column, e.Message, e.Line, e.Code)
+}
+
+// FormatSource prunes unused imports, adds missing imports from candidates and formats code with go/format.
+func FormatSource(params FormatParams) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, params.Filename, params.Source, parser.ParseComments)
+	if err != nil {
+		return nil, newFormatError(params, err)
+	}
+
+	imports := fixImports(file, params)
+
+	src := replaceImports(fset, file, params.Source, imports)
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, newFormatError(FormatParams{Filename: params.Filename, Source: src}, err)
+	}
+
+	return formatted, nil
+}
+
+func fixImports(file *ast.File, params FormatParams) *goimports.ImportGroups {
+	used := usedPackageNames(file)
+	provided := map[string]bool{}
+
+	imports := goimports.NewImportGroups(params.Module)
+
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		alias := ""
+		if spec.Name != nil {
+			alias = spec.Name.Name
+		}
+
+		name, resolved := importName(alias, path, params.PackageNames)
+		if resolved && alias != "_" && alias != "." && !used[name] && params.canPrune(name) {
+			continue
+		}
+
+		provided[name] = true
+		imports.Add(alias, path)
+	}
+
+	candidates := importCandidates(params.Candidates, params.PackageNames)
+
+	for name := range used {
+		if provided[name] {
+			continue
+		}
+
+		candidate, ok := candidates[name]
+		if !ok {
+			continue
+		}
+
+		provided[name] = true
+		imports.Add(candidate.Alias, candidate.Package.Path)
+	}
+
+	return imports
+}
+
+func (p *FormatParams) canPrune(name string) bool {
+	return p.Prunable == nil || p.Prunable[name]
+}
+
+// usedPackageNames returns names of selector expressions which aren't declared in file, e.g. "fmt" in "fmt.Sprintf".
+func usedPackageNames(file *ast.File) map[string]bool {
+	used := map[string]bool{}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		ident, ok := sel.X.(*ast.Ident)
+		if ok && ident.Obj == nil {
+			used[ident.Name] = true
+		}
+
+		return true
+	})
+
+	return used
+}
+
+func importCandidates(groups []*goimports.ImportGroups, names *PackageNames) map[string]goimports.GoImport {
+	candidates := map[string]goimports.GoImport{}
+
+	add := func(imp goimports.GoImport) {
+		name, _ := importName(imp.Alias, imp.Package.Path, names)
+		if _, exists := candidates[name]; !exists {
+			candidates[name] = imp
+		}
+	}
+
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+
+		for _, imps := range group.SortedImports() {
+			for _, imp := range imps {
+				add(imp)
+			}
+		}
+	}
+
+	std := goimports.NewImportGroups("")
+	for _, path := range stdImportCandidates {
+		std.Add("", path)
+	}
+
+	for _, imps := range std.SortedImports() {
+		for _, imp := range imps {
+			add(imp)
+		}
+	}
+
+	return candidates
+}
+
+// importName returns name, which code uses for import, and whether name is known for sure.
+// Unresolved name is guessed by path: last element without major version suffix.
+func importName(alias, path string, names *PackageNames) (string, bool) {
+	if alias != "" {
+		return alias, true
+	}
+
+	if name, ok := names.Resolve(path); ok {
+		return name, true
+	}
+
+	name := guessImportName(path)
+
+	// std packages are named by last element of path
+	return name, isStdImport(path) && name == path[strings.LastIndex(path, "/")+1:]
+}
+
+// guessImportName returns conventional name of package by path:
+// "github.com/go-chi/chi/v5" -> "chi", "gopkg.in/yaml.v3" -> "yaml", "github.com/a/go-redis" -> "redis".
+func guessImportName(path string) string {
+	elems := strings.Split(path, "/")
+
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+
+	if strings.HasPrefix(path, "gopkg.in/") {
+		if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+			name = name[:i]
+		}
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.LastIndexAny(name, "-."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+
+	for _, r := range elem[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// replaceImports cuts import declarations from source and puts grouped imports after package clause.
+func replaceImports(fset *token.FileSet, file *ast.File, src []byte, imports *goimports.ImportGroups) []byte {
+	buf := bytes.Buffer{}
+
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	pkgEnd := offset(file.Name.End())
+
+	buf.Write(src[:pkgEnd])
+
+	groups := imports.SortedImports()
+	if len(groups) > 0 {
+		buf.WriteString("\n\nimport (\n")
+
+		for i, group := range groups {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+
+			for _, imp := range group {
+				buf.WriteString("\t" + imp.GoString() + "\n")
+			}
+		}
+
+		buf.WriteString(")")
+	}
+
+	last := pkgEnd
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		buf.Write(src[last:offset(gen.Pos())])
+		last = offset(gen.End())
+	}
+
+	buf.Write(src[last:])
+
+	return buf.Bytes()
+}
+
+func newFormatError(params FormatParams, err error) error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return fmt.Errorf("failed to format %s: %w", params.Filename, err)
+	}
+
+	pos := list[0].Pos
+
+	lines := strings.Split(string(params.Source), "\n")
+	code := ""
+	if pos.Line > 0 && pos.Line <= len(lines) {
+		code = lines[pos.Line-1]
+	}
+
+	return &FormatError{
+		Filename: params.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Message:  list[0].Msg,
+		Code:     code,
+	}
+}
@@ -0,0 +1,210 @@
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/artarts36/gomodfinder"
+)
+
+func TestGuessImportName(t *testing.T) {
+	cases := []struct {
+		path string
+		name string
+	}{
+		{path: "fmt", name: "fmt"},
+		{path: "net/http", name: "http"},
+		{path: "math/rand/v2", name: "rand"},
+		{path: "github.com/go-chi/chi/v5", name: "chi"},
+		{path: "gopkg.in/yaml.v3", name: "yaml"},
+		{path: "github.com/redis/go-redis", name: "redis"},
+		{path: "github.com/artarts36/single-cli", name: "cli"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			if got := guessImportName(c.path); got != c.name {
+				t.Errorf("guessImportName(%q) = %q, want %q", c.path, got, c.name)
+			}
+		})
+	}
+}
+
+func TestImportName(t *testing.T) {
+	cases := []struct {
+		alias    string
+		path     string
+		name     string
+		resolved bool
+	}{
+		{path: "fmt", name: "fmt", resolved: true},
+		{path: "net/http", name: "http", resolved: true},
+		{alias: "v5", path: "github.com/go-chi/chi/v5", name: "v5", resolved: true},
+		{path: "github.com/go-chi/chi/v5", name: "chi", resolved: false},
+		{path: "gopkg.in/yaml.v3", name: "yaml", resolved: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			name, resolved := importName(c.alias, c.path, nil)
+			if name != c.name || resolved != c.resolved {
+				t.Errorf("importName(%q, %q) = (%q, %v), want (%q, %v)", c.alias, c.path, name, resolved, c.name, c.resolved)
+			}
+		})
+	}
+}
+
+func TestFormatSource(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		contains []string
+		missing  []string
+	}{
+		{
+			name: "prunes unused std import",
+			source: `package p
+
+import (
+	"fmt"
+	"strings"
+)
+
+func F() string { return fmt.Sprint(1) }
+`,
+			contains: []string{`"fmt"`},
+			missing:  []string{`"strings"`},
+		},
+		{
+			name: "adds missing std import",
+			source: `package p
+
+func F() error { return errors.New("e") }
+`,
+			contains: []string{`"errors"`},
+		},
+		{
+			name: "keeps import with unresolved name",
+			source: `package p
+
+import (
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
+)
+
+var r chi.Router
+
+var _ = yaml.Marshal
+`,
+			contains: []string{`"github.com/go-chi/chi/v5"`, `"gopkg.in/yaml.v3"`},
+		},
+		{
+			name: "keeps unused import with unresolved name",
+			source: `package p
+
+import "github.com/go-chi/chi/v5"
+
+func F() {}
+`,
+			contains: []string{`"github.com/go-chi/chi/v5"`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			formatted, err := FormatSource(FormatParams{
+				Filename: "p.go",
+				Source:   []byte(c.source),
+				Module:   "example.com/m",
+			})
+			if err != nil {
+				t.Fatalf("FormatSource() error = %v", err)
+			}
+
+			for _, s := range c.contains {
+				if !strings.Contains(string(formatted), s) {
+					t.Errorf("formatted code doesn't contain %s:\n%s", s, formatted)
+				}
+			}
+
+			for _, s := range c.missing {
+				if strings.Contains(string(formatted), s) {
+					t.Errorf("formatted code contains %s:\n%s", s, formatted)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatSourceReportsError(t *testing.T) {
+	_, err := FormatSource(FormatParams{
+		Filename: "p.go",
+		Source:   []byte("package p\n\nfunc F( {\n}\n"),
+	})
+
+	formatErr, ok := err.(*FormatError)
+	if !ok {
+		t.Fatalf("FormatSource() error = %v, want *FormatError", err)
+	}
+
+	if formatErr.Line != 3 {
+		t.Errorf("FormatError.Line = %d, want 3", formatErr.Line)
+	}
+}
+
+func TestPackageNamesResolve(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/m\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "api", "v2", "api.go"), "package contracts\n")
+
+	goMod, err := gomodfinder.Find(dir, 1)
+	if err != nil {
+		t.Fatalf("failed to find go.mod: %v", err)
+	}
+
+	names := NewPackageNames(goMod)
+
+	if name, ok := names.Resolve("example.com/m/api/v2"); !ok || name != "contracts" {
+		t.Errorf("Resolve() = (%q, %v), want (contracts, true)", name, ok)
+	}
+
+	if _, ok := names.Resolve("example.com/m/missing"); ok {
+		t.Errorf("Resolve() of missing package must be unresolved")
+	}
+
+	formatted, err := FormatSource(FormatParams{
+		Filename: "p.go",
+		Source: []byte(`package p
+
+import "example.com/m/api/v2"
+
+var _ contracts.Client
+`),
+		Module:       "example.com/m",
+		PackageNames: names,
+	})
+	if err != nil {
+		t.Fatalf("FormatSource() error = %v", err)
+	}
+
+	if !strings.Contains(string(formatted), `"example.com/m/api/v2"`) {
+		t.Errorf("import of used package is pruned:\n%s", formatted)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -24,6 +24,7 @@ type ParseInterfacesParams struct {
 	SourcePath     string
 	FilterNames    []string
 	SourceGoModule *gomodfinder.ModFile
+	TypeCheck      bool
 }
 
 func ParseInterfacesFromSource(params ParseInterfacesParams) (*File, error) {
@@ -66,6 +67,14 @@ func ParseInterfacesFromSource(params ParseInterfacesParams) (*File, error) {
 		}
 	}
 
+	resolver := NewTypeResolver(parsedFile)
+	if params.TypeCheck {
+		err = resolver.Check(fset, parsedFile, params.SourcePath, pkg.FullName(), params.SourceGoModule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to type check source package: %w", err)
+		}
+	}
+
 	var inspectErr error
 
 	ast.Inspect(parsedFile, func(x ast.Node) bool {
@@ -91,7 +100,13 @@ func ParseInterfacesFromSource(params ParseInterfacesParams) (*File, error) {
 		}
 
 		for _, method := range it.Methods.List {
-			goMethod, goMethodErr := ParseMethodFromField(method, pkg, importsShortnameMap, params.SourceGoModule.Module.Mod.Path)
+			goMethod, goMethodErr := ParseMethodFromField(
+				method,
+				pkg,
+				importsShortnameMap,
+				params.SourceGoModule.Module.Mod.Path,
+				resolver,
+			)
 			if goMethodErr != nil {
 				inspectErr = fmt.Errorf("failed to parse method for interface %q: %w", goInterface.Name, goMethodErr)
 				return false
@@ -115,3 +130,30 @@ func ParseInterfacesFromSource(params ParseInterfacesParams) (*File, error) {
 
 	return file, inspectErr
 }
+
+// InterfaceDecls returns code of interfaces declared in source by their names.
+func InterfaceDecls(filename string, src []byte) (map[string]string, error) {
+	fset := token.NewFileSet()
+
+	parsedFile, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", filename, err)
+	}
+
+	decls := map[string]string{}
+
+	ast.Inspect(parsedFile, func(x ast.Node) bool {
+		spec, ok := x.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+
+		if _, ok = spec.Type.(*ast.InterfaceType); ok {
+			decls[spec.Name.Name] = string(src[fset.Position(spec.Pos()).Offset:fset.Position(spec.End()).Offset])
+		}
+
+		return false
+	})
+
+	return decls, nil
+}
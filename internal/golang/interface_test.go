@@ -0,0 +1,37 @@
+package golang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterfaceDecls(t *testing.T) {
+	src := []byte(`package contracts
+
+type User struct{}
+
+type (
+	Users interface {
+		Get(id int) (User, error)
+	}
+
+	Notifier interface{ Notify() }
+)
+
+type ID int
+`)
+
+	decls, err := InterfaceDecls("contracts.go", src)
+	if err != nil {
+		t.Fatalf("InterfaceDecls() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Users":    "Users interface {\n\t\tGet(id int) (User, error)\n\t}",
+		"Notifier": "Notifier interface{ Notify() }",
+	}
+
+	if !reflect.DeepEqual(decls, want) {
+		t.Errorf("InterfaceDecls() = %q, want %q", decls, want)
+	}
+}
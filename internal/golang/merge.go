@@ -0,0 +1,260 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"github.com/artarts36/goimports"
+)
+
+type MergeParams struct {
+	Filename string
+
+	// Existing is code of file, which must be kept.
+	Existing []byte
+	// Generated is new generated code of the same file.
+	Generated []byte
+
+	Module       string
+	Candidates   []*goimports.ImportGroups
+	PackageNames *PackageNames
+}
+
+// MergeSource appends to existing code declarations of generated code, which are missing in existing code:
+// methods, functions, types, vars and consts. Existing declarations, comments and helpers are kept as is.
+// Returns names of added declarations, merged code is nil when nothing added.
+func MergeSource(params MergeParams) ([]byte, []string, error) {
+	fset := token.NewFileSet()
+
+	existing, err := parser.ParseFile(fset, params.Filename, params.Existing, parser.ParseComments)
+	if err != nil {
+		return nil, nil, newFormatError(FormatParams{Filename: params.Filename, Source: params.Existing}, err)
+	}
+
+	generated, err := parser.ParseFile(fset, params.Filename, params.Generated, parser.ParseComments)
+	if err != nil {
+		return nil, nil, newFormatError(FormatParams{Filename: params.Filename, Source: params.Generated}, err)
+	}
+
+	declared := map[string]bool{}
+	for _, decl := range existing.Decls {
+		for _, name := range declNames(decl) {
+			declared[name] = true
+		}
+	}
+
+	text := func(node ast.Node) []byte {
+		return params.Generated[fset.Position(node.Pos()).Offset:fset.Position(node.End()).Offset]
+	}
+
+	merged := bytes.NewBuffer(bytes.TrimRight(params.Existing, "\n"))
+	added := make([]string, 0)
+
+	for _, decl := range generated.Decls {
+		names := declNames(decl)
+
+		missing := make([]string, 0, len(names))
+		for _, name := range names {
+			if !declared[name] {
+				missing = append(missing, name)
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		added = append(added, missing...)
+
+		gen, isGen := decl.(*ast.GenDecl)
+		if !isGen || len(missing) == len(names) {
+			merged.WriteString("\n\n")
+			if doc := declDoc(decl); doc != nil {
+				merged.Write(text(doc))
+				merged.WriteString("\n")
+			}
+			merged.Write(text(decl))
+
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			if !declared[specNames(spec)[0]] {
+				merged.WriteString(fmt.Sprintf("\n\n%s ", gen.Tok))
+				merged.Write(text(spec))
+			}
+		}
+	}
+
+	if len(added) == 0 {
+		return nil, nil, nil
+	}
+
+	merged.WriteString("\n")
+
+	candidates := append([]*goimports.ImportGroups{
+		goimports.NewImportGroupsFromAstImportSpecs(generated.Imports, params.Module),
+	}, params.Candidates...)
+
+	formatted, err := FormatSource(FormatParams{
+		Filename:   params.Filename,
+		Source:     merged.Bytes(),
+		Module:     params.Module,
+		Candidates: candidates,
+
+		PackageNames: params.PackageNames,
+		// existing code is written by users, its imports are kept as is
+		Prunable: map[string]bool{},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return formatted, added, nil
+}
+
+// declNames returns names of declaration: "Type.Method" for methods, names of specs for type, var and const.
+func declNames(decl ast.Decl) []string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return []string{d.Name.Name}
+		}
+
+		return []string{receiverTypeName(d.Recv.List[0].Type) + "." + d.Name.Name}
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return nil
+		}
+
+		names := make([]string, 0, len(d.Specs))
+		for _, spec := range d.Specs {
+			names = append(names, specNames(spec)...)
+		}
+
+		return names
+	}
+
+	return nil
+}
+
+func specNames(spec ast.Spec) []string {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return []string{s.Name.Name}
+	case *ast.ValueSpec:
+		names := make([]string, 0, len(s.Names))
+		for _, name := range s.Names {
+			names = append(names, name.Name)
+		}
+
+		return names
+	}
+
+	return nil
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+
+	return ""
+}
+
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return d.Doc
+	case *ast.GenDecl:
+		return d.Doc
+	}
+
+	return nil
+}
+
+// DeclDrift is difference of declaration between existing and generated code.
+type DeclDrift struct {
+	// Name is name of declaration: "Type.Method", "Type", "Func".
+	Name   string
+	Change string
+}
+
+const (
+	DeclMissing    = "missing"
+	DeclChanged    = "changed"
+	DeclUnexpected = "unexpected"
+)
+
+// CompareDecls returns declarations, which differ in existing and generated code.
+func CompareDecls(filename string, existing, generated []byte) ([]DeclDrift, error) {
+	existingDecls, err := declTexts(filename, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	generatedDecls, err := declTexts(filename, generated)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]DeclDrift, 0)
+
+	for _, name := range generatedDecls.names {
+		existingText, exists := existingDecls.texts[name]
+
+		switch {
+		case !exists:
+			drifts = append(drifts, DeclDrift{Name: name, Change: DeclMissing})
+		case existingText != generatedDecls.texts[name]:
+			drifts = append(drifts, DeclDrift{Name: name, Change: DeclChanged})
+		}
+	}
+
+	for _, name := range existingDecls.names {
+		if _, exists := generatedDecls.texts[name]; !exists {
+			drifts = append(drifts, DeclDrift{Name: name, Change: DeclUnexpected})
+		}
+	}
+
+	return drifts, nil
+}
+
+type declTextMap struct {
+	names []string
+	texts map[string]string
+}
+
+func declTexts(filename string, src []byte) (*declTextMap, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, newFormatError(FormatParams{Filename: filename, Source: src}, err)
+	}
+
+	decls := &declTextMap{
+		texts: map[string]string{},
+	}
+
+	for _, decl := range file.Decls {
+		text := string(src[fset.Position(decl.Pos()).Offset:fset.Position(decl.End()).Offset])
+
+		for _, name := range declNames(decl) {
+			decls.names = append(decls.names, name)
+			decls.texts[name] = text
+		}
+	}
+
+	return decls, nil
+}
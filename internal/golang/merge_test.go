@@ -0,0 +1,135 @@
+package golang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeSource(t *testing.T) {
+	existing := `package p
+
+import (
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Stub struct{}
+
+// Get is edited by hand.
+func (s *Stub) Get() string {
+	return strings.ToUpper("get")
+}
+
+func route(r chi.Router) {}
+`
+
+	generated := `package p
+
+import "errors"
+
+type Stub struct{}
+
+func (s *Stub) Get() string {
+	return ""
+}
+
+func (s *Stub) Delete() error {
+	return errors.New("not implemented")
+}
+`
+
+	merged, added, err := MergeSource(MergeParams{
+		Filename:  "p.go",
+		Existing:  []byte(existing),
+		Generated: []byte(generated),
+		Module:    "example.com/m",
+	})
+	if err != nil {
+		t.Fatalf("MergeSource() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(added, []string{"Stub.Delete"}) {
+		t.Errorf("MergeSource() added = %v, want [Stub.Delete]", added)
+	}
+
+	for _, s := range []string{
+		`"strings"`,
+		`"github.com/go-chi/chi/v5"`,
+		`"errors"`,
+		"// Get is edited by hand.",
+		`strings.ToUpper("get")`,
+		"func (s *Stub) Delete() error",
+	} {
+		if !strings.Contains(string(merged), s) {
+			t.Errorf("merged code doesn't contain %s:\n%s", s, merged)
+		}
+	}
+}
+
+func TestMergeSourceKeepsUnusedImportsOfUser(t *testing.T) {
+	existing := `package p
+
+import "strings"
+
+type Stub struct{}
+`
+
+	generated := `package p
+
+type Stub struct{}
+
+func (s *Stub) Ping() {}
+`
+
+	merged, _, err := MergeSource(MergeParams{
+		Filename:  "p.go",
+		Existing:  []byte(existing),
+		Generated: []byte(generated),
+	})
+	if err != nil {
+		t.Fatalf("MergeSource() error = %v", err)
+	}
+
+	if !strings.Contains(string(merged), `"strings"`) {
+		t.Errorf("import of user is pruned:\n%s", merged)
+	}
+}
+
+func TestMergeSourceUpToDate(t *testing.T) {
+	src := []byte("package p\n\ntype Stub struct{}\n\nfunc (s *Stub) Ping() {}\n")
+
+	merged, added, err := MergeSource(MergeParams{
+		Filename:  "p.go",
+		Existing:  src,
+		Generated: src,
+	})
+	if err != nil {
+		t.Fatalf("MergeSource() error = %v", err)
+	}
+
+	if merged != nil || len(added) != 0 {
+		t.Errorf("MergeSource() = (%s, %v), want nothing merged", merged, added)
+	}
+}
+
+func TestCompareDecls(t *testing.T) {
+	existing := []byte("package p\n\nfunc (s *Stub) A() {}\n\nfunc (s *Stub) B() {}\n\nfunc Old() {}\n")
+	generated := []byte("package p\n\nfunc (s *Stub) A() {}\n\nfunc (s *Stub) B() { panic(1) }\n\nfunc (s *Stub) C() {}\n")
+
+	drifts, err := CompareDecls("p.go", existing, generated)
+	if err != nil {
+		t.Fatalf("CompareDecls() error = %v", err)
+	}
+
+	want := []DeclDrift{
+		{Name: "Stub.B", Change: DeclChanged},
+		{Name: "Stub.C", Change: DeclMissing},
+		{Name: "Old", Change: DeclUnexpected},
+	}
+
+	if !reflect.DeepEqual(drifts, want) {
+		t.Errorf("CompareDecls() = %v, want %v", drifts, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/artarts36/goimports"
 	"github.com/artarts36/gomodfinder"
 	"go/ast"
+	"strings"
 
 	"github.com/artarts36/gostub/internal/ds"
 )
@@ -16,6 +17,51 @@ type GoMethod struct {
 	Results      *GoParameters
 	UsedPackages *ds.Set[string]
 	Imports      *goimports.ImportGroups
+	Directives   Directives
+}
+
+func (m *GoMethod) ReturnsError() bool {
+	if len(m.Results.List) == 0 {
+		return false
+	}
+
+	return m.Results.List[len(m.Results.List)-1].Type.IsError()
+}
+
+func (m *GoMethod) ReturnsOnlyError() bool {
+	return len(m.Results.List) == 1 && m.ReturnsError()
+}
+
+func (m *GoMethod) ResultVars(errVar string) string {
+	vars := make([]string, 0, len(m.Results.List))
+	for i := range m.Results.List {
+		if i == len(m.Results.List)-1 && m.ReturnsError() {
+			vars = append(vars, errVar)
+			continue
+		}
+
+		vars = append(vars, m.LocalName(fmt.Sprintf("r%d", i)))
+	}
+
+	return strings.Join(vars, ", ")
+}
+
+// LocalName returns name of local variable, which doesn't conflict with names of parameters and results:
+// "err" -> "err1" when result is named "err".
+func (m *GoMethod) LocalName(base string) string {
+	taken := map[string]bool{}
+	for _, list := range []*GoParameters{m.Parameters, m.Results} {
+		for _, param := range list.List {
+			taken[param.Name] = true
+		}
+	}
+
+	name := base
+	for i := 1; taken[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+
+	return name
 }
 
 func ParseMethodFromField(
@@ -23,6 +69,7 @@ func ParseMethodFromField(
 	pkg *gomodfinder.Package,
 	imports *gds.Map[string, goimports.GoImport],
 	goModule string,
+	resolver *TypeResolver,
 ) (*GoMethod, error) {
 	goMethod := &GoMethod{
 		Name:         ds.NewString(method.Names[0].Name),
@@ -30,6 +77,7 @@ func ParseMethodFromField(
 		Imports:      goimports.NewImportGroups(goModule),
 		Parameters:   &GoParameters{List: make([]GoParameter, 0)},
 		Results:      &GoParameters{List: make([]GoParameter, 0)},
+		Directives:   parseDirectives(method.Doc, method.Comment),
 	}
 
 	mFunc, mFuncOk := method.Type.(*ast.FuncType)
@@ -47,7 +95,7 @@ func ParseMethodFromField(
 				Name: param.Names[0].Name,
 			}
 
-			goParamType, paramErr := parseParameterType(param.Type, pkg)
+			goParamType, paramErr := parseParameterType(param.Type, pkg, resolver)
 			if paramErr != nil {
 				return nil, fmt.Errorf(
 					"failed to get type name for %s.%s: %w",
@@ -86,7 +134,7 @@ func ParseMethodFromField(
 				result.Name = resultNode.Names[0].Name
 			}
 
-			paramType, paramTypeErr := parseParameterType(resultNode.Type, pkg)
+			paramType, paramTypeErr := parseParameterType(resultNode.Type, pkg, resolver)
 			if paramTypeErr != nil {
 				return nil, fmt.Errorf(
 					"failed to parse result[%d] type for method %q: %w",
@@ -96,7 +144,7 @@ func ParseMethodFromField(
 				)
 			}
 
-			paramType.calcStubInstantiateExpr(goMethod.Name.String())
+			paramType.calcStubInstantiateExpr()
 
 			if paramType.ValueThroughVar {
 				goMethod.Results.HasValueThroughAnyArg = true
@@ -117,3 +165,27 @@ func ParseMethodFromField(
 
 	return goMethod, nil
 }
+
+// Signature returns method as it is declared in interface: Name(a int, b string) (string, error).
+func (m *GoMethod) Signature() string {
+	params := make([]string, 0, len(m.Parameters.List))
+	for _, param := range m.Parameters.List {
+		params = append(params, strings.TrimSpace(param.Name+" "+param.Type.Name))
+	}
+
+	results := make([]string, 0, len(m.Results.List))
+	for _, result := range m.Results.List {
+		results = append(results, strings.TrimSpace(result.Name+" "+result.Type.Name))
+	}
+
+	signature := fmt.Sprintf("%s(%s)", m.Name.Value, strings.Join(params, ", "))
+
+	switch {
+	case len(results) == 1 && m.Results.List[0].Name == "":
+		signature += " " + results[0]
+	case len(results) > 0:
+		signature += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	return signature
+}
@@ -0,0 +1,61 @@
+package golang
+
+import "testing"
+
+func TestGoMethodLocalNames(t *testing.T) {
+	errorType := GoParameterType{Name: TypeError}
+	stringType := GoParameterType{Name: TypeString}
+
+	cases := []struct {
+		name       string
+		params     []GoParameter
+		results    []GoParameter
+		localName  string
+		resultVars string
+	}{
+		{
+			name:       "unnamed results",
+			results:    []GoParameter{{Type: stringType}, {Type: errorType}},
+			localName:  "err",
+			resultVars: "r0, err",
+		},
+		{
+			name:       "result named err",
+			results:    []GoParameter{{Name: "value", Type: stringType}, {Name: "err", Type: errorType}},
+			localName:  "err1",
+			resultVars: "r0, err1",
+		},
+		{
+			name:       "parameters named err and r0",
+			params:     []GoParameter{{Name: "err", Type: stringType}, {Name: "r0", Type: stringType}},
+			results:    []GoParameter{{Type: stringType}, {Type: errorType}},
+			localName:  "err1",
+			resultVars: "r01, err1",
+		},
+		{
+			name:       "taken suffix",
+			params:     []GoParameter{{Name: "err", Type: stringType}, {Name: "err1", Type: stringType}},
+			results:    []GoParameter{{Type: errorType}},
+			localName:  "err2",
+			resultVars: "err2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			method := &GoMethod{
+				Parameters: &GoParameters{List: c.params},
+				Results:    &GoParameters{List: c.results},
+			}
+
+			localName := method.LocalName("err")
+			if localName != c.localName {
+				t.Errorf("LocalName() = %q, want %q", localName, c.localName)
+			}
+
+			if got := method.ResultVars(localName); got != c.resultVars {
+				t.Errorf("ResultVars() = %q, want %q", got, c.resultVars)
+			}
+		})
+	}
+}
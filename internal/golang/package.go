@@ -1,5 +1,14 @@
 package golang
 
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
 type Package struct {
 	Name                string
 	FullName            string
@@ -12,3 +21,94 @@ type UsedPackage struct {
 	Alias   string
 	Package Package
 }
+
+// DetectPackageName returns name of package declared by .go files in dir.
+// Returns empty string when dir doesn't exist or doesn't contain .go files, test packages are ignored.
+func DetectPackageName(dir string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+
+	name := ""
+
+	for _, path := range paths {
+		file, parseErr := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+		if parseErr != nil {
+			return "", fmt.Errorf("failed to parse package clause of %q: %w", path, parseErr)
+		}
+
+		current := file.Name.Name
+		if strings.HasSuffix(current, "_test") {
+			continue
+		}
+
+		if name != "" && name != current {
+			return "", fmt.Errorf("directory %q contains different packages: %s, %s", dir, name, current)
+		}
+
+		name = current
+	}
+
+	return name, nil
+}
+
+// PackageNameFromDir returns valid package name based on directory name: "rpc-service" -> "rpcservice".
+func PackageNameFromDir(dir string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, filepath.Base(dir))
+
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "pkg" + name
+	}
+
+	return name
+}
+
+// DeclaredNames returns names of declarations in .go files of dir, excluding files by their cleaned paths.
+// Test files are ignored, returns empty set when dir doesn't exist.
+func DeclaredNames(dir string, exclude map[string]bool) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+
+	for _, path := range paths {
+		if exclude[filepath.Clean(path)] || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, parseErr := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", path, parseErr)
+		}
+
+		for _, decl := range file.Decls {
+			for _, name := range declNames(decl) {
+				names[name] = true
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// DeclNames returns names of declarations of code in order of declaration.
+func DeclNames(filename string, src []byte) ([]string, error) {
+	decls, err := declTexts(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	return decls.names, nil
+}
@@ -0,0 +1,153 @@
+package golang
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/artarts36/gomodfinder"
+	"golang.org/x/mod/module"
+)
+
+// PackageNames resolves names of imported packages by package clauses of their sources:
+// std packages from GOROOT, packages of module from its directory, dependencies from vendor or module cache.
+type PackageNames struct {
+	moduleDir  string
+	modulePath string
+
+	// dirs is directories of required modules by their paths.
+	dirs map[string]string
+
+	resolved map[string]string
+}
+
+func NewPackageNames(goMod *gomodfinder.ModFile) *PackageNames {
+	names := &PackageNames{
+		moduleDir:  filepath.Dir(goMod.Path),
+		modulePath: goMod.Module.Mod.Path,
+		dirs:       map[string]string{},
+		resolved:   map[string]string{},
+	}
+
+	cache := modCacheDir()
+
+	for _, req := range goMod.Require {
+		path, err := module.EscapePath(req.Mod.Path)
+		if err != nil {
+			continue
+		}
+
+		version, err := module.EscapeVersion(req.Mod.Version)
+		if err != nil {
+			continue
+		}
+
+		names.dirs[req.Mod.Path] = filepath.Join(cache, filepath.FromSlash(path)+"@"+version)
+	}
+
+	for _, rep := range goMod.Replace {
+		if rep.New.Version == "" {
+			names.dirs[rep.Old.Path] = filepath.Join(names.moduleDir, filepath.FromSlash(rep.New.Path))
+
+			continue
+		}
+
+		path, err := module.EscapePath(rep.New.Path)
+		if err != nil {
+			continue
+		}
+
+		version, err := module.EscapeVersion(rep.New.Version)
+		if err != nil {
+			continue
+		}
+
+		names.dirs[rep.Old.Path] = filepath.Join(cache, filepath.FromSlash(path)+"@"+version)
+	}
+
+	return names
+}
+
+// Resolve returns name of package by import path, false when package sources can't be found.
+func (n *PackageNames) Resolve(path string) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+
+	if name, ok := n.resolved[path]; ok {
+		return name, name != ""
+	}
+
+	name := ""
+
+	for _, dir := range n.packageDirs(path) {
+		detected, err := DetectPackageName(dir)
+		if err == nil && detected != "" {
+			name = detected
+
+			break
+		}
+	}
+
+	n.resolved[path] = name
+
+	return name, name != ""
+}
+
+func (n *PackageNames) packageDirs(path string) []string {
+	if isStdImport(path) {
+		return []string{filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(path))}
+	}
+
+	dirs := make([]string, 0)
+
+	if rel, ok := cutModulePath(path, n.modulePath); ok {
+		dirs = append(dirs, filepath.Join(n.moduleDir, filepath.FromSlash(rel)))
+	}
+
+	dirs = append(dirs, filepath.Join(n.moduleDir, "vendor", filepath.FromSlash(path)))
+
+	// the longest module path owns package
+	owner := ""
+	for modPath := range n.dirs {
+		if _, ok := cutModulePath(path, modPath); ok && len(modPath) > len(owner) {
+			owner = modPath
+		}
+	}
+
+	if owner != "" {
+		rel, _ := cutModulePath(path, owner)
+		dirs = append(dirs, filepath.Join(n.dirs[owner], filepath.FromSlash(rel)))
+	}
+
+	return dirs
+}
+
+// cutModulePath returns path of package relative to module.
+func cutModulePath(path, modulePath string) (string, bool) {
+	if path == modulePath {
+		return "", true
+	}
+
+	rel, ok := strings.CutPrefix(path, modulePath+"/")
+
+	return rel, ok
+}
+
+func isStdImport(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+func modCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = build.Default.GOPATH
+	}
+
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
@@ -0,0 +1,85 @@
+package golang
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectPackageName(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "package of files",
+			files: map[string]string{
+				"a.go":      "package impl\n",
+				"b.go":      "// Package impl.\npackage impl\n",
+				"a_test.go": "package impl_test\n",
+			},
+			want: "impl",
+		},
+		{
+			name: "different packages",
+			files: map[string]string{
+				"a.go": "package a\n",
+				"b.go": "package b\n",
+			},
+			wantErr: "contains different packages: a, b",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for name, content := range c.files {
+				writeFile(t, filepath.Join(dir, name), content)
+			}
+
+			got, err := DetectPackageName(dir)
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Errorf("DetectPackageName() error = %v, want %q", err, c.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("DetectPackageName() error = %v", err)
+			}
+
+			if got != c.want {
+				t.Errorf("DetectPackageName() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDetectPackageNameOfMissingDir(t *testing.T) {
+	got, err := DetectPackageName(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || got != "" {
+		t.Errorf("DetectPackageName() = (%q, %v), want empty name", got, err)
+	}
+}
+
+func TestPackageNameFromDir(t *testing.T) {
+	for dir, want := range map[string]string{
+		"/a/implementations": "implementations",
+		"./rpc-service":      "rpcservice",
+		"/a/Mocks_v2":        "mocks_v2",
+		"/a/2fa":             "pkg2fa",
+		"/a/--":              "pkg",
+	} {
+		if got := PackageNameFromDir(dir); got != want {
+			t.Errorf("PackageNameFromDir(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/artarts36/gomodfinder"
 	"github.com/artarts36/gostub/internal/ds"
 	"go/ast"
-	"html/template"
+	"go/types"
+	"strings"
 )
 
 type GoParameters struct {
@@ -14,6 +15,48 @@ type GoParameters struct {
 	HasValueThroughAnyArg bool
 }
 
+func (p *GoParameters) CallArgs() string {
+	names := make([]string, 0, len(p.List))
+	for _, param := range p.List {
+		names = append(names, param.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (p *GoParameters) Context() string {
+	for _, param := range p.List {
+		if param.Type.IsContext() {
+			return param.Name
+		}
+	}
+
+	return ""
+}
+
+func (p *GoParameters) LeadingContext() string {
+	if len(p.List) > 0 && p.List[0].Type.IsContext() {
+		return p.List[0].Name
+	}
+
+	return ""
+}
+
+func (p *GoParameters) NonContext() *GoParameters {
+	params := &GoParameters{
+		List:                  make([]GoParameter, 0, len(p.List)),
+		HasValueThroughAnyArg: p.HasValueThroughAnyArg,
+	}
+
+	for _, param := range p.List {
+		if !param.Type.IsContext() {
+			params.List = append(params.List, param)
+		}
+	}
+
+	return params
+}
+
 type GoParameter struct {
 	Name string
 	Type GoParameterType
@@ -28,8 +71,13 @@ type GoParameterType struct {
 	ValueThroughNil bool
 	ValueThroughVar bool
 
-	Value         template.HTML
-	ExternalValue template.HTML
+	Value         string
+	ExternalValue string
+
+	Kind     TypeKind
+	Elem     *GoParameterType
+	ChanDir  ast.ChanDir
+	Resolved types.Type
 
 	Package *gomodfinder.Package
 }
@@ -42,31 +90,34 @@ func (t *GoParameterType) Call(pkg *gomodfinder.Package) string {
 	return t.ExternalName
 }
 
+func (t *GoParameterType) IsError() bool {
+	return t.Name == TypeError
+}
+
+func (t *GoParameterType) IsContext() bool {
+	return t.Name == "context.Context"
+}
+
 func (t *GoParameterType) String() string {
 	return t.Name
 }
 
-func (t *GoParameterType) ValueFor(pkg *gomodfinder.Package) template.HTML {
+func (t *GoParameterType) ValueFor(pkg *gomodfinder.Package) string {
 	if t.Package.Equal(pkg) {
 		return t.Value
 	}
 	return t.ExternalValue
 }
 
-func (t *GoParameterType) calcStubInstantiateExpr(methodName string) {
-	calc := func(methodName string) (template.HTML, template.HTML) {
+func (t *GoParameterType) calcStubInstantiateExpr() {
+	calc := func() (string, string) {
 		if t.ValueThroughNil {
 			return TypeNil, TypeNil
 		}
 
 		if !t.UsedPackages.Valid() {
 			switch t.Name {
-			case TypeError:
-				t.UsedPackages.Add("errors")
-
-				return template.HTML(fmt.Sprintf(`errors.New("is not real method %s")`, methodName)),
-					template.HTML(fmt.Sprintf(`errors.New("is not real method %s")`, methodName))
-			case TypeAny, "interface":
+			case TypeError, TypeAny, "interface":
 				return TypeNil, TypeNil
 			case TypeString:
 				return `""`, `""`
@@ -81,11 +132,11 @@ func (t *GoParameterType) calcStubInstantiateExpr(methodName string) {
 
 		t.ValueThroughVar = true
 
-		return template.HTML(fmt.Sprintf("any(0).(%s)", t.Name)),
-			template.HTML(fmt.Sprintf("any(0).(%s)", t.ExternalName))
+		return fmt.Sprintf("any(0).(%s)", t.Name),
+			fmt.Sprintf("any(0).(%s)", t.ExternalName)
 	}
 
-	t.Value, t.ExternalValue = calc(methodName)
+	t.Value, t.ExternalValue = calc()
 }
 
 func calcPtVtn(result GoParameterType, ptNode ast.Node) {
@@ -100,15 +151,39 @@ func calcPtVtn(result GoParameterType, ptNode ast.Node) {
 	}
 }
 
-func parseParameterType(ptNode ast.Node, pkg *gomodfinder.Package) (GoParameterType, error) { //nolint:funlen,lll // not need
+func parseParameterType(ptNode ast.Node, pkg *gomodfinder.Package, resolver *TypeResolver) (GoParameterType, error) { //nolint:funlen,gocognit,lll // not need
 	result := GoParameterType{
 		Name:         "",
 		UsedPackages: ds.NewSet[string](),
 		Package:      pkg,
+		Kind:         resolveTypeKind(ptNode, resolver.Decls),
+		Resolved:     resolver.TypeOf(ptNode),
+	}
+
+	if result.Kind == TypeKindNamed {
+		result.Kind = namedTypeKind(result.Resolved)
 	}
 
 	calcPtVtn(result, ptNode)
 
+	var elemNode ast.Node
+	switch pt := ptNode.(type) {
+	case *ast.StarExpr:
+		elemNode = pt.X
+	case *ast.ChanType:
+		elemNode = pt.Value
+		result.ChanDir = pt.Dir
+	}
+
+	if elemNode != nil {
+		elem, err := parseParameterType(elemNode, pkg, resolver)
+		if err != nil {
+			return result, err
+		}
+
+		result.Elem = &elem
+	}
+
 	var parse func(node ast.Node) (string, string, error)
 
 	parse = func(node ast.Node) (string, string, error) {
@@ -168,6 +243,21 @@ func parseParameterType(ptNode ast.Node, pkg *gomodfinder.Package) (GoParameterT
 			}
 
 			return fmt.Sprintf("map[%s]%s", key, value), fmt.Sprintf("map[%s]%s", extKey, extVal), nil
+		case *ast.ChanType:
+			value, extVal, err := parse(pt.Value)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to parse chan value: %w", err)
+			}
+
+			prefix := "chan "
+			switch pt.Dir {
+			case ast.RECV:
+				prefix = "<-chan "
+			case ast.SEND:
+				prefix = "chan<- "
+			}
+
+			return prefix + value, prefix + extVal, nil
 		case *ast.BasicLit:
 			return pt.Value, pt.Value, nil
 		case *ast.FuncType:
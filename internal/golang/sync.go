@@ -0,0 +1,204 @@
+package golang
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/artarts36/goimports"
+)
+
+// MethodSignature is signature of method declared in generated code.
+type MethodSignature struct {
+	// Key is "Type.Method".
+	Key string
+	// Signature is parameters and results: "(ctx context.Context) error".
+	Signature string
+
+	types string
+}
+
+type SyncParams struct {
+	Filename string
+	Source   []byte
+
+	// Expected is signatures of methods by key "Type.Method".
+	Expected map[string]*MethodSignature
+	// Types is names of types which methods must be synced.
+	Types map[string]bool
+
+	Module       string
+	Candidates   []*goimports.ImportGroups
+	PackageNames *PackageNames
+}
+
+type SyncResult struct {
+	// Source is code with fixed signatures, nil when nothing changed.
+	Source []byte
+	// Fixed is keys of methods with fixed signatures.
+	Fixed []string
+	// Orphaned is keys of exported methods, which are missing in interfaces.
+	Orphaned []string
+}
+
+// ExtractMethodSignatures returns signatures of methods declared in code.
+func ExtractMethodSignatures(filename string, src []byte) (map[string]*MethodSignature, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, newFormatError(FormatParams{Filename: filename, Source: src}, err)
+	}
+
+	signatures := map[string]*MethodSignature{}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			continue
+		}
+
+		key := declNames(fn)[0]
+
+		signatures[key] = &MethodSignature{
+			Key:       key,
+			Signature: string(src[fset.Position(fn.Type.Params.Pos()).Offset:fset.Position(fn.Type.End()).Offset]),
+			types:     signatureTypes(fset, fn.Type),
+		}
+	}
+
+	return signatures, nil
+}
+
+// SyncSource rewrites parameters and results of methods, which signature differs from expected.
+// Bodies, receivers and other declarations are kept as is.
+func SyncSource(params SyncParams) (*SyncResult, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, params.Filename, params.Source, parser.ParseComments)
+	if err != nil {
+		return nil, newFormatError(FormatParams{Filename: params.Filename, Source: params.Source}, err)
+	}
+
+	result := &SyncResult{}
+
+	type replacement struct {
+		start, end int
+		text       string
+	}
+
+	replacements := make([]replacement, 0)
+
+	// only imports used by replaced signatures can become unused, other imports of users are kept
+	prunable := map[string]bool{}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			continue
+		}
+
+		if !params.Types[receiverTypeName(fn.Recv.List[0].Type)] {
+			continue
+		}
+
+		key := declNames(fn)[0]
+
+		expected, exists := params.Expected[key]
+		if !exists {
+			if fn.Name.IsExported() {
+				result.Orphaned = append(result.Orphaned, key)
+			}
+
+			continue
+		}
+
+		if signatureTypes(fset, fn.Type) == expected.types {
+			continue
+		}
+
+		ast.Inspect(fn.Type, func(node ast.Node) bool {
+			if sel, isSel := node.(*ast.SelectorExpr); isSel {
+				if ident, isIdent := sel.X.(*ast.Ident); isIdent {
+					prunable[ident.Name] = true
+				}
+			}
+
+			return true
+		})
+
+		result.Fixed = append(result.Fixed, key)
+		replacements = append(replacements, replacement{
+			start: fset.Position(fn.Type.Params.Pos()).Offset,
+			end:   fset.Position(fn.Type.End()).Offset,
+			text:  expected.Signature,
+		})
+	}
+
+	if len(replacements) == 0 {
+		return result, nil
+	}
+
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	src := bytes.Buffer{}
+	last := 0
+
+	for _, r := range replacements {
+		src.Write(params.Source[last:r.start])
+		src.WriteString(r.text)
+		last = r.end
+	}
+
+	src.Write(params.Source[last:])
+
+	result.Source, err = FormatSource(FormatParams{
+		Filename:   params.Filename,
+		Source:     src.Bytes(),
+		Module:     params.Module,
+		Candidates: append([]*goimports.ImportGroups{goimports.NewImportGroupsFromAstImportSpecs(file.Imports, params.Module)}, params.Candidates...),
+
+		PackageNames: params.PackageNames,
+		Prunable:     prunable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// signatureTypes returns types of parameters and results without names.
+func signatureTypes(fset *token.FileSet, fn *ast.FuncType) string {
+	fields := func(list *ast.FieldList) string {
+		if list == nil {
+			return ""
+		}
+
+		types := make([]string, 0, list.NumFields())
+
+		for _, field := range list.List {
+			buf := bytes.Buffer{}
+			_ = printer.Fprint(&buf, fset, field.Type)
+
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+
+			for i := 0; i < count; i++ {
+				types = append(types, buf.String())
+			}
+		}
+
+		return strings.Join(types, ", ")
+	}
+
+	return "(" + fields(fn.Params) + ") (" + fields(fn.Results) + ")"
+}
@@ -0,0 +1,105 @@
+package golang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncSource(t *testing.T) {
+	generated := []byte(`package p
+
+import "context"
+
+func (s *Stub) Get(ctx context.Context, id string) (string, error) {
+	panic("not implemented")
+}
+`)
+
+	existing := []byte(`package p
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Stub struct {
+	r chi.Router
+}
+
+func (s *Stub) Get(d time.Duration) string {
+	return strings.Repeat("a", int(d))
+}
+
+func (s *Stub) Removed() {}
+
+func (s *Stub) helper() {}
+`)
+
+	expected, err := ExtractMethodSignatures("gen.go", generated)
+	if err != nil {
+		t.Fatalf("ExtractMethodSignatures() error = %v", err)
+	}
+
+	result, err := SyncSource(SyncParams{
+		Filename: "p.go",
+		Source:   existing,
+		Expected: expected,
+		Types:    map[string]bool{"Stub": true},
+		Module:   "example.com/m",
+	})
+	if err != nil {
+		t.Fatalf("SyncSource() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Fixed, []string{"Stub.Get"}) {
+		t.Errorf("SyncSource() fixed = %v, want [Stub.Get]", result.Fixed)
+	}
+
+	if !reflect.DeepEqual(result.Orphaned, []string{"Stub.Removed"}) {
+		t.Errorf("SyncSource() orphaned = %v, want [Stub.Removed]", result.Orphaned)
+	}
+
+	src := string(result.Source)
+
+	for _, s := range []string{
+		"func (s *Stub) Get(ctx context.Context, id string) (string, error) {",
+		`"context"`,
+		`"strings"`,
+		`"github.com/go-chi/chi/v5"`,
+	} {
+		if !strings.Contains(src, s) {
+			t.Errorf("synced code doesn't contain %s:\n%s", s, src)
+		}
+	}
+
+	// time was used only by replaced signature
+	if strings.Contains(src, `"time"`) {
+		t.Errorf("synced code contains unused import of replaced signature:\n%s", src)
+	}
+}
+
+func TestSyncSourceUpToDate(t *testing.T) {
+	src := []byte("package p\n\nfunc (s *Stub) Ping(id int) error {\n\treturn nil\n}\n")
+
+	expected, err := ExtractMethodSignatures("gen.go", src)
+	if err != nil {
+		t.Fatalf("ExtractMethodSignatures() error = %v", err)
+	}
+
+	result, err := SyncSource(SyncParams{
+		Filename: "p.go",
+		Source:   src,
+		Expected: expected,
+		Types:    map[string]bool{"Stub": true},
+	})
+	if err != nil {
+		t.Fatalf("SyncSource() error = %v", err)
+	}
+
+	if result.Source != nil || len(result.Fixed) != 0 {
+		t.Errorf("SyncSource() = %+v, want nothing fixed", result)
+	}
+}
@@ -1,6 +1,8 @@
 package golang
 
 import (
+	"fmt"
+
 	"github.com/artarts36/goimports"
 	"github.com/artarts36/gomodfinder"
 )
@@ -26,3 +28,11 @@ func (t *Type) Clone() Type {
 		Interface: t.Interface,
 	}
 }
+
+func (t *Type) InterfaceName() string {
+	if t.Package.Equal(t.Interface.Package) {
+		return t.Interface.Name.Value
+	}
+
+	return fmt.Sprintf("%s.%s", t.Interface.Package.Name, t.Interface.Name.Value)
+}
@@ -0,0 +1,166 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/artarts36/gomodfinder"
+)
+
+type TypeKind int
+
+const (
+	TypeKindUnknown TypeKind = iota
+	TypeKindBasic
+	TypeKindError
+	TypeKindInterface
+	TypeKindContext
+	TypeKindStruct
+	TypeKindPointer
+	TypeKindSlice
+	TypeKindArray
+	TypeKindMap
+	TypeKindChan
+	TypeKindNamed
+)
+
+type TypeDecls map[string]ast.Expr
+
+func CollectTypeDecls(file *ast.File) TypeDecls {
+	decls := TypeDecls{}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if ok {
+			decls[spec.Name.Name] = spec.Type
+		}
+
+		return true
+	})
+
+	return decls
+}
+
+func resolveTypeKind(node ast.Node, decls TypeDecls) TypeKind {
+	switch pt := node.(type) {
+	case *ast.Ident:
+		switch {
+		case pt.Name == TypeError:
+			return TypeKindError
+		case pt.Name == TypeAny || pt.Name == "interface":
+			return TypeKindInterface
+		case IsStdType(pt.Name):
+			return TypeKindBasic
+		}
+
+		decl, ok := decls[pt.Name]
+		if !ok {
+			return TypeKindNamed
+		}
+
+		switch kind := resolveTypeKind(decl, TypeDecls{}); kind { //nolint:exhaustive // other kinds has nil zero value
+		case TypeKindStruct, TypeKindSlice, TypeKindArray, TypeKindMap:
+			return kind
+		}
+
+		return TypeKindNamed
+	case *ast.SelectorExpr:
+		switch fmt.Sprintf("%s.%s", pt.X, pt.Sel.Name) {
+		case "context.Context":
+			return TypeKindContext
+		case "time.Time":
+			return TypeKindStruct
+		}
+
+		return TypeKindNamed
+	case *ast.StarExpr:
+		return TypeKindPointer
+	case *ast.ArrayType:
+		if pt.Len == nil {
+			return TypeKindSlice
+		}
+
+		return TypeKindArray
+	case *ast.MapType:
+		return TypeKindMap
+	case *ast.ChanType:
+		return TypeKindChan
+	case *ast.StructType:
+		return TypeKindStruct
+	case *ast.InterfaceType:
+		return TypeKindInterface
+	}
+
+	return TypeKindUnknown
+}
+
+// namedTypeKind returns kind of named type declared out of source file by its underlying type,
+// resolved by type checker. Returns TypeKindNamed when type isn't resolved.
+func namedTypeKind(typ types.Type) TypeKind {
+	if typ == nil {
+		return TypeKindNamed
+	}
+
+	switch typ.Underlying().(type) {
+	case *types.Struct:
+		return TypeKindStruct
+	case *types.Slice:
+		return TypeKindSlice
+	case *types.Array:
+		return TypeKindArray
+	case *types.Map:
+		return TypeKindMap
+	}
+
+	return TypeKindNamed
+}
+
+func (t *GoParameterType) DefaultFor(pkg *gomodfinder.Package) string {
+	name := t.Call(pkg)
+
+	switch t.Kind {
+	case TypeKindBasic:
+		if value, ok := basicZeroValue(t.Name); ok {
+			return value
+		}
+	case TypeKindError, TypeKindInterface:
+		return TypeNil
+	case TypeKindContext:
+		return "context.Background()"
+	case TypeKindStruct, TypeKindSlice, TypeKindArray, TypeKindMap:
+		return fmt.Sprintf("%s{}", name)
+	case TypeKindPointer:
+		switch t.Elem.Kind { //nolint:exhaustive // other kinds can't be created with literal
+		case TypeKindStruct, TypeKindSlice, TypeKindArray, TypeKindMap:
+			return fmt.Sprintf("&%s{}", t.Elem.Call(pkg))
+		}
+
+		return fmt.Sprintf("new(%s)", t.Elem.Call(pkg))
+	case TypeKindChan:
+		if t.ChanDir == ast.RECV {
+			return fmt.Sprintf("func() %s { ch := make(chan %s); close(ch); return ch }()", name, t.Elem.Call(pkg))
+		}
+
+		return fmt.Sprintf("make(%s)", name)
+	case TypeKindNamed, TypeKindUnknown:
+	}
+
+	return fmt.Sprintf("*new(%s)", name)
+}
+
+func (t *GoParameterType) ZeroFor(pkg *gomodfinder.Package) string {
+	switch t.Kind {
+	case TypeKindBasic:
+		if value, ok := basicZeroValue(t.Name); ok {
+			return value
+		}
+	case TypeKindError, TypeKindInterface, TypeKindContext, TypeKindPointer, TypeKindSlice, TypeKindMap, TypeKindChan:
+		return TypeNil
+	case TypeKindStruct, TypeKindArray:
+		return fmt.Sprintf("%s{}", t.Call(pkg))
+	case TypeKindNamed, TypeKindUnknown:
+	}
+
+	return fmt.Sprintf("*new(%s)", t.Call(pkg))
+}
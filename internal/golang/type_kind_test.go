@@ -0,0 +1,143 @@
+package golang
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/artarts36/gomodfinder"
+)
+
+func TestZeroForAndDefaultForOfParameters(t *testing.T) {
+	pkg := &gomodfinder.Package{Name: "stubs", Path: "example.com/m/stubs"}
+	other := &gomodfinder.Package{Name: "contracts", Path: "example.com/m/contracts"}
+
+	cases := []struct {
+		name        string
+		typ         *GoParameterType
+		wantZero    string
+		wantDefault string
+	}{
+		{
+			name:        "int",
+			typ:         &GoParameterType{Name: "int", ExternalName: "int", Kind: TypeKindBasic},
+			wantZero:    "0",
+			wantDefault: "0",
+		},
+		{
+			name:        "string",
+			typ:         &GoParameterType{Name: "string", ExternalName: "string", Kind: TypeKindBasic},
+			wantZero:    `""`,
+			wantDefault: `""`,
+		},
+		{
+			name:        "bool",
+			typ:         &GoParameterType{Name: "bool", ExternalName: "bool", Kind: TypeKindBasic},
+			wantZero:    "false",
+			wantDefault: "false",
+		},
+		{
+			name:        "slice",
+			typ:         &GoParameterType{Name: "[]User", ExternalName: "[]contracts.User", Kind: TypeKindSlice, Package: other},
+			wantZero:    "nil",
+			wantDefault: "[]contracts.User{}",
+		},
+		{
+			name:        "named",
+			typ:         &GoParameterType{Name: "ID", ExternalName: "contracts.ID", Kind: TypeKindNamed, Package: other},
+			wantZero:    "*new(contracts.ID)",
+			wantDefault: "*new(contracts.ID)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// values are calculated only for results, parameters have empty values
+			if got := c.typ.ZeroFor(pkg); got != c.wantZero {
+				t.Errorf("ZeroFor() = %q, want %q", got, c.wantZero)
+			}
+
+			if got := c.typ.DefaultFor(pkg); got != c.wantDefault {
+				t.Errorf("DefaultFor() = %q, want %q", got, c.wantDefault)
+			}
+		})
+	}
+}
+
+func TestParseInterfacesResolvesKindsOfTypesDeclaredOutOfSource(t *testing.T) {
+	// other packages are type checked from sources of module
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/m\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "models", "models.go"), `package models
+
+type User struct {
+	Name string
+}
+
+type Attrs map[string]string
+`)
+	writeFile(t, filepath.Join(dir, "contracts", "types.go"), `package contracts
+
+type Tags []string
+
+type ID int
+`)
+
+	source := filepath.Join(dir, "contracts", "contracts.go")
+	src := `package contracts
+
+import "example.com/m/models"
+
+type Users interface {
+	Get(id ID) (models.User, *models.User, models.Attrs, Tags, ID, error)
+}
+`
+	writeFile(t, source, src)
+
+	goMod, err := gomodfinder.Find(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ParseInterfacesFromSource(ParseInterfacesParams{
+		Source:         []byte(src),
+		SourcePath:     source,
+		SourceGoModule: goMod,
+		TypeCheck:      true,
+	})
+	if err != nil {
+		t.Fatalf("ParseInterfacesFromSource() error = %v", err)
+	}
+
+	pkg := &gomodfinder.Package{Name: "stubs", Path: "example.com/m/stubs"}
+
+	want := []struct {
+		kind  TypeKind
+		value string
+	}{
+		{kind: TypeKindStruct, value: "models.User{}"},
+		{kind: TypeKindPointer, value: "&models.User{}"},
+		{kind: TypeKindMap, value: "models.Attrs{}"},
+		{kind: TypeKindSlice, value: "contracts.Tags{}"},
+		{kind: TypeKindNamed, value: "*new(contracts.ID)"},
+		{kind: TypeKindError, value: "nil"},
+	}
+
+	results := file.Interfaces[0].Methods[0].Results.List
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+
+	for i, result := range results {
+		if result.Type.Kind != want[i].kind {
+			t.Errorf("kind of result %d = %d, want %d", i, result.Type.Kind, want[i].kind)
+		}
+
+		if got := result.Type.DefaultFor(pkg); got != want[i].value {
+			t.Errorf("DefaultFor() of result %d = %q, want %q", i, got, want[i].value)
+		}
+	}
+}
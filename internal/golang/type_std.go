@@ -12,10 +12,13 @@ const (
 
 var (
 	stdTypes = []string{
-		"int", "int16", "int32",
-		"uint8", "uint16", "uint32", "uint64",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"byte", "rune",
 		"float32", "float64",
 
+		"complex64", "complex128",
+
 		"string",
 
 		"error",
@@ -28,9 +31,11 @@ var (
 	}
 
 	numericTypes = []string{
-		"int", "int16", "int32",
-		"uint8", "uint16", "uint32", "uint64",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"byte", "rune",
 		"float32", "float64",
+		"complex64", "complex128",
 	}
 )
 
@@ -41,3 +46,17 @@ func IsNumericType(name string) bool {
 func IsStdType(name string) bool {
 	return slices.Contains(stdTypes, name)
 }
+
+// basicZeroValue returns zero value of std type, which isn't nil.
+func basicZeroValue(name string) (string, bool) {
+	switch {
+	case name == TypeString:
+		return `""`, true
+	case name == TypeBool:
+		return "false", true
+	case IsNumericType(name):
+		return "0", true
+	}
+
+	return "", false
+}
@@ -0,0 +1,42 @@
+package golang
+
+import (
+	"testing"
+
+	"github.com/artarts36/gostub/internal/ds"
+)
+
+func TestStubInstantiateExprOfStdTypes(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+	}{
+		{name: "int", value: "0"},
+		{name: "int64", value: "0"},
+		{name: "uintptr", value: "0"},
+		{name: "rune", value: "0"},
+		{name: "float64", value: "0"},
+		{name: "complex64", value: "0"},
+		{name: "complex128", value: "0"},
+		{name: "string", value: `""`},
+		{name: "bool", value: "false"},
+		{name: "error", value: "nil"},
+		{name: "any", value: "nil"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			typ := &GoParameterType{
+				Name:         c.name,
+				ExternalName: c.name,
+				UsedPackages: ds.NewSet[string](),
+			}
+
+			typ.calcStubInstantiateExpr()
+
+			if typ.Value != c.value || typ.ValueThroughVar {
+				t.Errorf("value of %s = %q (through var: %v), want %q", c.name, typ.Value, typ.ValueThroughVar, c.value)
+			}
+		})
+	}
+}
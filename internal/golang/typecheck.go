@@ -0,0 +1,157 @@
+package golang
+
+import (
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/artarts36/gomodfinder"
+)
+
+type TypeResolver struct {
+	Decls TypeDecls
+	Info  *types.Info
+}
+
+func NewTypeResolver(file *ast.File) *TypeResolver {
+	return &TypeResolver{
+		Decls: CollectTypeDecls(file),
+	}
+}
+
+// Check type-checks package of file together with sibling files from sourcePath directory.
+// Type errors are ignored: partially resolved types are enough for generation.
+func (r *TypeResolver) Check(
+	fset *token.FileSet,
+	file *ast.File,
+	sourcePath string,
+	pkgPath string,
+	goMod *gomodfinder.ModFile,
+) error {
+	siblings, err := parsePackageFiles(fset, filepath.Dir(sourcePath), file.Name.Name, sourcePath)
+	if err != nil {
+		return err
+	}
+
+	r.Info = &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+	}
+
+	conf := types.Config{
+		Importer: newPackageImporter(fset, goMod),
+		Error:    func(error) {},
+	}
+
+	_, _ = conf.Check(pkgPath, fset, append([]*ast.File{file}, siblings...), r.Info)
+
+	return nil
+}
+
+func (r *TypeResolver) TypeOf(node ast.Node) types.Type {
+	expr, ok := node.(ast.Expr)
+	if !ok || r.Info == nil {
+		return nil
+	}
+
+	return r.Info.TypeOf(expr)
+}
+
+// packageImporter type-checks imported packages from their sources, found in module directory,
+// vendor or module cache. Std packages are imported by source importer of go/build.
+//
+// Source importer isn't used for packages of module, because go/build resolves them
+// from working directory instead of module of source file.
+type packageImporter struct {
+	fset     *token.FileSet
+	names    *PackageNames
+	std      types.Importer
+	packages map[string]*types.Package
+}
+
+func newPackageImporter(fset *token.FileSet, goMod *gomodfinder.ModFile) *packageImporter {
+	return &packageImporter{
+		fset:     fset,
+		names:    NewPackageNames(goMod),
+		std:      importer.ForCompiler(fset, "source", nil),
+		packages: map[string]*types.Package{},
+	}
+}
+
+func (i *packageImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i.packages[path]; ok {
+		return pkg, nil
+	}
+
+	if path == "unsafe" || isStdImport(path) {
+		return i.std.Import(path)
+	}
+
+	for _, dir := range i.names.packageDirs(path) {
+		files, err := parsePackageFiles(i.fset, dir, "", "")
+		if err != nil || len(files) == 0 {
+			continue
+		}
+
+		conf := types.Config{
+			Importer: i,
+			Error:    func(error) {},
+		}
+
+		pkg, _ := conf.Check(path, i.fset, files, nil)
+		i.packages[path] = pkg
+
+		return pkg, nil
+	}
+
+	return i.std.Import(path)
+}
+
+// parsePackageFiles parses non-test go files of package from dir, which match build constraints.
+// Package name is taken from first file when name is empty. File by skipPath is skipped.
+func parsePackageFiles(fset *token.FileSet, dir, name, skipPath string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*ast.File, 0, len(entries))
+
+	for _, entry := range entries {
+		filename := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(filename, ".go") || strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		path := filepath.Join(dir, filename)
+		if path == skipPath {
+			continue
+		}
+
+		if match, matchErr := build.Default.MatchFile(dir, filename); matchErr != nil || !match {
+			continue
+		}
+
+		file, parseErr := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if parseErr != nil {
+			continue
+		}
+
+		if name == "" {
+			name = file.Name.Name
+		}
+
+		if file.Name.Name != name {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
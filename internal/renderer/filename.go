@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/artarts36/gostub/internal/golang"
-	"html/template"
+	"text/template"
 )
 
 type NameGenerator struct {
@@ -25,22 +25,22 @@ func NewNameGenerator(
 
 	generator := &NameGenerator{}
 
-	generator.commonFilenameTpl, err = template.New("stub-common-filename-template").Parse(commonFilenameTpl)
+	generator.commonFilenameTpl, err = template.New("stub-common-filename-template").Funcs(funcs()).Parse(commonFilenameTpl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile common filename template: %w", err)
 	}
 
-	generator.perMethodFilenameTpl, err = template.New("stub-per-method-filename-template").Parse(perMethodFileTpl)
+	generator.perMethodFilenameTpl, err = template.New("stub-per-method-filename-template").Funcs(funcs()).Parse(perMethodFileTpl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile stub per method filename template: %w", err)
 	}
 
-	generator.typeNameTpl, err = template.New("stub-struct-name-template").Parse(typeNameTpl)
+	generator.typeNameTpl, err = template.New("stub-struct-name-template").Funcs(funcs()).Parse(typeNameTpl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile struct name template: %w", err)
 	}
 
-	generator.perTypeFilenameTpl, err = template.New("stub-per-struct-name-filename-template").Parse(perTypeFilenameTpl)
+	generator.perTypeFilenameTpl, err = template.New("stub-per-struct-name-filename-template").Funcs(funcs()).Parse(perTypeFilenameTpl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile struct name template: %w", err)
 	}
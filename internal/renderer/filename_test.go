@@ -0,0 +1,45 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/artarts36/gostub/internal/ds"
+	"github.com/artarts36/gostub/internal/golang"
+)
+
+func TestNameGenerator(t *testing.T) {
+	generator, err := NewNameGenerator(
+		"stubs & mocks.go",
+		"{{ .Interface.Name.Snake.Value }}_{{ .Method.Name.Snake.Value }}.go",
+		"{{ snake .Interface.Name.Value }}'s.go",
+		"Stub{{ .Interface.Name.Pascal.Value }}",
+	)
+	if err != nil {
+		t.Fatalf("NewNameGenerator() error = %v", err)
+	}
+
+	iface := &golang.GoInterface{Name: ds.NewString("UserService")}
+
+	common, err := generator.GenerateCommonFilename()
+	if err != nil || common != "stubs & mocks.go" {
+		t.Errorf("GenerateCommonFilename() = (%q, %v), want stubs & mocks.go", common, err)
+	}
+
+	perType, err := generator.GenerateStubStructFilename(iface)
+	if err != nil || perType != "user_service's.go" {
+		t.Errorf("GenerateStubStructFilename() = (%q, %v), want user_service's.go", perType, err)
+	}
+
+	perMethod, err := generator.GenerateStubPerMethodFilename(
+		golang.Type{Interface: iface},
+		&golang.GoMethod{Name: ds.NewString("GetByID")},
+	)
+	if err != nil || perMethod != "user_service_get_by_id.go" {
+		t.Errorf("GenerateStubPerMethodFilename() = (%q, %v), want user_service_get_by_id.go", perMethod, err)
+	}
+
+	typeName, err := generator.GenerateTypeName(iface)
+	if err != nil || typeName != "StubUserService" {
+		t.Errorf("GenerateTypeName() = (%q, %v), want StubUserService", typeName, err)
+	}
+}
@@ -0,0 +1,129 @@
+package renderer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/artarts36/gomodfinder"
+	"github.com/iancoleman/strcase"
+	"github.com/jinzhu/inflection"
+
+	"github.com/artarts36/gostub/internal/golang"
+)
+
+// funcs returns functions shared by code templates and name templates.
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		"isLast": func(index int, arr interface{}) bool {
+			return index == reflect.ValueOf(arr).Len()-1
+		},
+		"hasNext": func(index int, arr interface{}) bool {
+			return index < reflect.ValueOf(arr).Len()-1
+		},
+		"noEmpty": func(item interface{}) bool {
+			if item == nil {
+				return false
+			}
+
+			switch v := item.(type) {
+			case interface{ Len() int }:
+				return v.Len() > 0
+			}
+
+			return reflect.ValueOf(item).Len() > 0
+		},
+		"isOnce": func(arr interface{}) bool {
+			return reflect.ValueOf(arr).Len() == 1
+		},
+		"isMany": func(arr interface{}) bool {
+			return reflect.ValueOf(arr).Len() > 1
+		},
+		// raw is kept for templates written for html/template, text/template never escapes values.
+		"raw": func(val string) string {
+			return val
+		},
+		"lowerFirst": func(val string) string {
+			if val == "" {
+				return val
+			}
+
+			return strings.ToLower(val[:1]) + val[1:]
+		},
+		"snake":  strcase.ToSnake,
+		"camel":  strcase.ToLowerCamel,
+		"pascal": strcase.ToCamel,
+		"plural": inflection.Plural,
+		"quote":  strconv.Quote,
+		"join":   join,
+		"indent": indent,
+		"typeOf": func(param interface{}, pkg *gomodfinder.Package) (string, error) {
+			typ, err := paramType(param)
+			if err != nil {
+				return "", err
+			}
+
+			return typ.Call(pkg), nil
+		},
+		"zeroOf": func(param interface{}, pkg *gomodfinder.Package) (string, error) {
+			typ, err := paramType(param)
+			if err != nil {
+				return "", err
+			}
+
+			return typ.ZeroFor(pkg), nil
+		},
+	}
+}
+
+func join(sep string, list interface{}) (string, error) {
+	switch v := list.(type) {
+	case []string:
+		return strings.Join(v, sep), nil
+	case string:
+		return v, nil
+	}
+
+	val := reflect.ValueOf(list)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: expected list, got %T", list)
+	}
+
+	items := make([]string, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		items = append(items, fmt.Sprint(val.Index(i).Interface()))
+	}
+
+	return strings.Join(items, sep), nil
+}
+
+// indent prefixes every non-empty line of val with n tabs.
+func indent(n int, val string) string {
+	prefix := strings.Repeat("\t", n)
+
+	lines := strings.Split(val, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func paramType(param interface{}) (*golang.GoParameterType, error) {
+	switch v := param.(type) {
+	case *golang.GoParameter:
+		return &v.Type, nil
+	case golang.GoParameter:
+		return &v.Type, nil
+	case *golang.GoParameterType:
+		return v, nil
+	case golang.GoParameterType:
+		return &v, nil
+	}
+
+	return nil, fmt.Errorf("expected parameter or parameter type, got %T", param)
+}
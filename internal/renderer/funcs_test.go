@@ -0,0 +1,141 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/artarts36/gomodfinder"
+
+	"github.com/artarts36/gostub/internal/golang"
+)
+
+func renderString(t *testing.T, tpl string, params map[string]interface{}) string {
+	t.Helper()
+
+	rend, err := NewRenderer("")
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	err = rend.AddTemplate("test.tpl", tpl)
+	if err != nil {
+		t.Fatalf("AddTemplate() error = %v", err)
+	}
+
+	out := &bytes.Buffer{}
+
+	err = rend.Render(out, "test.tpl", params)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	return out.String()
+}
+
+func TestRenderDoesntEscapeCode(t *testing.T) {
+	params := map[string]interface{}{
+		"Code": `func() <-chan map[string]*T { return nil } // "a" && 'b'`,
+	}
+
+	got := renderString(t, "{{ .Code }}|{{ raw .Code }}", params)
+
+	want := params["Code"].(string) + "|" + params["Code"].(string)
+	if got != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	pkg := &gomodfinder.Package{Name: "stubs", Path: "example.com/m/stubs"}
+	contracts := &gomodfinder.Package{Name: "contracts", Path: "example.com/m/contracts"}
+
+	params := []golang.GoParameter{
+		{Name: "id", Type: golang.GoParameterType{Name: "int", ExternalName: "int", Kind: golang.TypeKindBasic}},
+		{Name: "result", Type: golang.GoParameterType{Name: "string", ExternalName: "string", Kind: golang.TypeKindBasic}},
+		{
+			Name: "user",
+			Type: golang.GoParameterType{
+				Name:         "*User",
+				ExternalName: "*contracts.User",
+				Kind:         golang.TypeKindPointer,
+				Package:      contracts,
+			},
+		},
+		{
+			Name: "tags",
+			Type: golang.GoParameterType{
+				Name:         "Tags",
+				ExternalName: "contracts.Tags",
+				Kind:         golang.TypeKindNamed,
+				Package:      contracts,
+			},
+		},
+	}
+
+	cases := []struct {
+		name string
+		tpl  string
+		want string
+	}{
+		{name: "snake", tpl: `{{ snake "UserService" }}`, want: "user_service"},
+		{name: "camel", tpl: `{{ camel "user_service" }}`, want: "userService"},
+		{name: "pascal", tpl: `{{ pascal "user_service" }}`, want: "UserService"},
+		{name: "plural", tpl: `{{ plural "category" }}`, want: "categories"},
+		{name: "quote", tpl: `{{ quote "say \"hi\"" }}`, want: `"say \"hi\""`},
+		{name: "lowerFirst", tpl: `{{ lowerFirst "UserService" }}`, want: "userService"},
+		{name: "join strings", tpl: `{{ join ", " .Names }}`, want: "a, b"},
+		{name: "join values", tpl: `{{ join "-" .Numbers }}`, want: "1-2-3"},
+		{name: "indent", tpl: `{{ indent 2 "a\n\nb" }}`, want: "\t\ta\n\n\t\tb"},
+		{
+			name: "typeOf",
+			tpl:  `{{ range .Params }}{{ typeOf . $.Package }};{{ end }}`,
+			want: "int;string;*contracts.User;contracts.Tags;",
+		},
+		{
+			name: "zeroOf",
+			tpl:  `{{ range .Params }}{{ .Name }}={{ zeroOf . $.Package }};{{ end }}`,
+			want: `id=0;result="";user=nil;tags=*new(contracts.Tags);`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := renderString(t, c.tpl, map[string]interface{}{
+				"Names":   []string{"a", "b"},
+				"Numbers": []int{1, 2, 3},
+				"Params":  params,
+				"Package": pkg,
+			})
+
+			if got != c.want {
+				t.Errorf("rendered %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFuncsErrors(t *testing.T) {
+	rend, err := NewRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tpl := range map[string]string{
+		"join of not list":        `{{ join "," 1 }}`,
+		"typeOf of not parameter": `{{ typeOf "int" nil }}`,
+		"zeroOf of not parameter": `{{ zeroOf 1 nil }}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			err = rend.AddTemplate("test.tpl", tpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = rend.Render(&bytes.Buffer{}, "test.tpl", nil)
+			if err == nil || !strings.Contains(err.Error(), "expected") {
+				t.Errorf("Render() error = %v, want error of function", err)
+			}
+		})
+	}
+}
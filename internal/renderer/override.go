@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// DataVersion is version of data model passed to templates, see templates/README.md.
+// Must be increased on every breaking change of the model.
+const DataVersion = 1
+
+var dataVersionRegexp = regexp.MustCompile(`\{\{/\*\s*gostub:data-version\s+(\d+)\s*\*/\}\}`)
+
+func (r *Renderer) overrideTemplates(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tpl"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return fmt.Errorf("failed to read %q: %w", path, readErr)
+		}
+
+		name := filepath.Base(path)
+
+		err = checkDataVersion(name, content)
+		if err != nil {
+			return err
+		}
+
+		if r.templates.Lookup(name) != nil {
+			slog.Info("[renderer] overriding template", slog.String("template", name), slog.String("path", path))
+		}
+
+		err = r.AddTemplate(name, string(content))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkDataVersion(name string, content []byte) error {
+	match := dataVersionRegexp.FindSubmatch(content)
+	if match == nil {
+		slog.Warn(
+			"[renderer] template doesn't declare data version",
+			slog.String("template", name),
+			slog.String("expected", fmt.Sprintf("{{/* gostub:data-version %d */}}", DataVersion)),
+		)
+
+		return nil
+	}
+
+	version, err := strconv.Atoi(string(match[1]))
+	if err != nil {
+		return fmt.Errorf("template %q has invalid data version: %w", name, err)
+	}
+
+	if version != DataVersion {
+		return fmt.Errorf(
+			"template %q written for data version %d, but gostub provides version %d: see templates/README.md",
+			name,
+			version,
+			DataVersion,
+		)
+	}
+
+	return nil
+}
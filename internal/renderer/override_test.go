@@ -0,0 +1,86 @@
+package renderer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewRendererOverridesTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"stub_errors.tpl": "{{/* gostub:data-version 1 */}}// errors of {{ .Team }}",
+		"banner.tpl":      "// banner of {{ .Team }}",
+	} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rend, err := NewRenderer(dir)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"stub_errors.tpl": "// errors of gostub",
+		"banner.tpl":      "// banner of gostub",
+	} {
+		out := &bytes.Buffer{}
+
+		err = rend.Render(out, name, map[string]interface{}{"Team": "gostub"})
+		if err != nil {
+			t.Fatalf("Render() of %s error = %v", name, err)
+		}
+
+		if out.String() != want {
+			t.Errorf("Render() of %s = %q, want %q", name, out.String(), want)
+		}
+	}
+
+	// templates which aren't overridden are taken from embedded ones
+	if rend.templates.Lookup("stub.tpl") == nil {
+		t.Error("embedded stub.tpl is missing")
+	}
+}
+
+func TestNewRendererRejectsTemplatesOfAnotherDataVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "stub.tpl"), []byte("{{/* gostub:data-version 2 */}}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewRenderer(dir)
+	if err == nil || !strings.Contains(err.Error(), `template "stub.tpl" written for data version 2`) {
+		t.Errorf("NewRenderer() error = %v, want error of data version", err)
+	}
+}
+
+func TestCheckDataVersion(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "current", content: "{{/* gostub:data-version 1 */}}code"},
+		{name: "without spaces", content: "{{/*gostub:data-version 1*/}}code"},
+		{name: "not declared", content: "code"},
+		{name: "another", content: "{{/* gostub:data-version 2 */}}code", wantErr: true},
+		{name: "too large", content: "{{/* gostub:data-version 99999999999999999999 */}}code", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkDataVersion("test.tpl", []byte(c.content))
+			if (err != nil) != c.wantErr {
+				t.Errorf("checkDataVersion() error = %v, want error: %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -4,81 +4,69 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/artarts36/gostub/templates"
-	"html/template"
 	"io"
-	"reflect"
+	"text/template"
 )
 
 type Renderer struct {
 	templates *template.Template
 }
 
-func NewRenderer() (*Renderer, error) {
+func NewRenderer(templatesDir string) (*Renderer, error) {
 	rend := &Renderer{}
 
-	tmpl, err := template.New("*.tpl").Funcs(template.FuncMap{
-		"include": func(tplName string, kv ...any) (template.HTML, error) {
-			params := map[string]interface{}{}
+	funcMap := funcs()
+	funcMap["include"] = func(tplName string, kv ...any) (string, error) {
+		params := map[string]interface{}{}
 
-			for i, keyOrVal := range kv {
-				if i%2 == 0 {
-					continue
-				}
-
-				key, ok := kv[i-1].(string)
-				if !ok {
-					return "", fmt.Errorf("invalid key %q at index %d", keyOrVal, i)
-				}
+		for i, keyOrVal := range kv {
+			if i%2 == 0 {
+				continue
+			}
 
-				params[key] = keyOrVal
+			key, ok := kv[i-1].(string)
+			if !ok {
+				return "", fmt.Errorf("invalid key %q at index %d", keyOrVal, i)
 			}
 
-			buf := bytes.Buffer{}
+			params[key] = keyOrVal
+		}
 
-			err := rend.Render(&buf, tplName, params)
-			if err != nil {
-				return "", err
-			}
+		buf := bytes.Buffer{}
 
-			return template.HTML(buf.String()), nil
-		},
-		"isLast": func(index int, arr interface{}) bool {
-			return index == reflect.ValueOf(arr).Len()-1
-		},
-		"hasNext": func(index int, arr interface{}) bool {
-			return index < reflect.ValueOf(arr).Len()-1
-		},
-		"noEmpty": func(item interface{}) bool {
-			if item == nil {
-				return false
-			}
+		err := rend.Render(&buf, tplName, params)
+		if err != nil {
+			return "", err
+		}
 
-			switch v := item.(type) {
-			case interface{ Len() int }:
-				return v.Len() > 0
-			}
+		return buf.String(), nil
+	}
 
-			return reflect.ValueOf(item).Len() > 0
-		},
-		"raw": func(val string) template.HTML {
-			return template.HTML(val)
-		},
-		"isOnce": func(arr interface{}) bool {
-			return reflect.ValueOf(arr).Len() == 1
-		},
-		"isMany": func(arr interface{}) bool {
-			return reflect.ValueOf(arr).Len() > 1
-		},
-	}).ParseFS(templates.FS, "*.tpl")
+	tmpl, err := template.New("*.tpl").Funcs(funcMap).ParseFS(templates.FS, "*.tpl")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse templates: %w", err)
 	}
 
 	rend.templates = tmpl
 
+	if templatesDir != "" {
+		err = rend.overrideTemplates(templatesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load templates from %q: %w", templatesDir, err)
+		}
+	}
+
 	return rend, nil
 }
 
+func (r *Renderer) AddTemplate(name string, content string) error {
+	_, err := r.templates.New(name).Parse(content)
+	if err != nil {
+		return fmt.Errorf("failed to parse template %q: %w", name, err)
+	}
+	return nil
+}
+
 func (r *Renderer) Render(w io.Writer, tplName string, params map[string]interface{}) error {
 	err := r.templates.ExecuteTemplate(w, tplName, params)
 	if err != nil {
@@ -0,0 +1,88 @@
+package stub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	MethodBodyPanic      = "panic"
+	MethodBodyNilReturns = "nil-returns"
+	MethodBodyDefaults   = "defaults"
+	MethodBodyFake       = "fake"
+
+	methodBodyFilePrefix = "file:"
+)
+
+type MethodBody struct {
+	Name string
+	Tpl  string
+
+	// File is path to user template, empty for builtin bodies.
+	File string
+	// TypeCheck resolves types declared out of source file, which are needed to build values of results.
+	TypeCheck bool
+}
+
+var methodBodies = map[string]*MethodBody{
+	MethodBodyPanic: {
+		Name: MethodBodyPanic,
+		Tpl:  "method_body_panic.tpl",
+	},
+	MethodBodyNilReturns: {
+		Name: MethodBodyNilReturns,
+		Tpl:  "method_body_nil_returns.tpl",
+	},
+	MethodBodyDefaults: {
+		Name:      MethodBodyDefaults,
+		Tpl:       "method_body_defaults.tpl",
+		TypeCheck: true,
+	},
+	MethodBodyFake: {
+		Name:      MethodBodyFake,
+		Tpl:       "method_body_fake.tpl",
+		TypeCheck: true,
+	},
+}
+
+func FindMethodBody(name string) (*MethodBody, error) {
+	if name == "" {
+		name = MethodBodyPanic
+	}
+
+	if path, ok := strings.CutPrefix(name, methodBodyFilePrefix); ok {
+		if path == "" {
+			return nil, fmt.Errorf("method body %q must contain path to template file", name)
+		}
+
+		return &MethodBody{
+			Name: name,
+			Tpl:  name,
+			File: path,
+		}, nil
+	}
+
+	body, ok := methodBodies[name]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown method body %q, available: %s or %s<path to template>",
+			name,
+			strings.Join(MethodBodyNames(), ", "),
+			methodBodyFilePrefix,
+		)
+	}
+
+	return body, nil
+}
+
+func MethodBodyNames() []string {
+	names := make([]string, 0, len(methodBodies))
+	for name := range methodBodies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
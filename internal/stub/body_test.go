@@ -0,0 +1,59 @@
+package stub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindMethodBody(t *testing.T) {
+	cases := []struct {
+		name     string
+		wantTpl  string
+		wantFile string
+	}{
+		{name: "", wantTpl: "method_body_panic.tpl"},
+		{name: MethodBodyPanic, wantTpl: "method_body_panic.tpl"},
+		{name: MethodBodyNilReturns, wantTpl: "method_body_nil_returns.tpl"},
+		{name: MethodBodyDefaults, wantTpl: "method_body_defaults.tpl"},
+		{name: MethodBodyFake, wantTpl: "method_body_fake.tpl"},
+		{name: "file:./tpl/todo_body.tpl", wantTpl: "file:./tpl/todo_body.tpl", wantFile: "./tpl/todo_body.tpl"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body, err := FindMethodBody(c.name)
+			if err != nil {
+				t.Fatalf("FindMethodBody() error = %v", err)
+			}
+
+			if body.Tpl != c.wantTpl || body.File != c.wantFile {
+				t.Errorf("FindMethodBody() = (%q, %q), want (%q, %q)", body.Tpl, body.File, c.wantTpl, c.wantFile)
+			}
+		})
+	}
+}
+
+func TestFindMethodBodyErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		wantErr string
+	}{
+		{
+			name:    "todo",
+			wantErr: `unknown method body "todo", available: defaults, fake, nil-returns, panic or file:<path to template>`,
+		},
+		{
+			name:    "file:",
+			wantErr: `method body "file:" must contain path to template file`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := FindMethodBody(c.name)
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("FindMethodBody() error = %v, want %q", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -17,7 +17,9 @@ type CollectParams struct {
 	TypePerFile   bool
 	MethodPerFile bool
 
+	Kind          *Kind
 	MethodBodyTpl string
+	CtxAware      bool
 
 	TargetPackage *gomodfinder.Package
 }
@@ -30,7 +32,14 @@ func (c *Collector) Collect(params *CollectParams, nameGenerator *renderer.NameG
 
 	if !params.TypePerFile && !params.MethodPerFile {
 		st, csErr := c.createCommonStub(types, params, nameGenerator)
-		return []*Stub{st}, csErr
+		if csErr != nil {
+			return nil, csErr
+		}
+
+		stubs := []*Stub{st}
+		c.attachErrors(stubs, params.Kind)
+
+		return stubs, nil
 	}
 
 	stubs := make([]*Stub, 0)
@@ -58,21 +67,46 @@ func (c *Collector) Collect(params *CollectParams, nameGenerator *renderer.NameG
 			return nil, fnerr
 		}
 
+		imports := goimports.NewImportGroups("")
+		c.addKindImports(imports, params.Kind, types, true, false)
+
 		stub := &Stub{
 			Filename:   filename,
 			Package:    types[0].Package,
-			Imports:    goimports.NewImportGroups(""),
+			Imports:    imports,
 			Types:      types,
 			GenMethods: false,
 			GenTypes:   true,
+			TypesTpl:   params.Kind.TypesTpl,
 		}
 
 		stubs = append(stubs, stub)
 	}
 
+	c.attachErrors(stubs, params.Kind)
+
 	return stubs, nil
 }
 
+func (c *Collector) attachErrors(stubs []*Stub, kind *Kind) {
+	if kind.ErrorsTpl == "" {
+		return
+	}
+
+	for _, stub := range stubs {
+		if !stub.GenTypes {
+			continue
+		}
+
+		stub.ErrorsTpl = kind.ErrorsTpl
+		for _, path := range kind.ErrorsImports {
+			stub.Imports.Add("", path)
+		}
+
+		return
+	}
+}
+
 func (c *Collector) createCommonStub(
 	types []golang.Type,
 	params *CollectParams,
@@ -88,14 +122,20 @@ func (c *Collector) createCommonStub(
 		pkg = types[0].Package
 	}
 
+	imports := types[0].Imports
+	c.addKindImports(imports, params.Kind, types, true, true)
+
 	return &Stub{
 		Filename:      filename,
 		Package:       pkg,
-		Imports:       types[0].Imports,
+		Imports:       imports,
 		Types:         types,
 		GenMethods:    true,
 		GenTypes:      true,
+		TypesTpl:      params.Kind.TypesTpl,
+		MethodTpl:     params.Kind.MethodTpl,
 		MethodBodyTpl: params.MethodBodyTpl,
+		CtxAware:      params.CtxAware,
 	}, nil
 }
 
@@ -112,16 +152,22 @@ func (c *Collector) collectPerType(
 			return nil, stfErr
 		}
 
+		imports := typ.Interface.Imports
+		c.addKindImports(imports, params.Kind, []golang.Type{typ}, true, !params.MethodPerFile)
+
 		stub := &Stub{
 			Filename: stubTypeFilename,
 			Package:  typ.Package,
-			Imports:  typ.Interface.Imports,
+			Imports:  imports,
 			Types: []golang.Type{
 				typ,
 			},
 			GenTypes:      true,
 			GenMethods:    !params.MethodPerFile,
+			TypesTpl:      params.Kind.TypesTpl,
+			MethodTpl:     params.Kind.MethodTpl,
 			MethodBodyTpl: params.MethodBodyTpl,
+			CtxAware:      params.CtxAware,
 		}
 
 		stubs = append(stubs, stub)
@@ -187,6 +233,8 @@ func (c *Collector) collectMethodStubs(
 				imports.AddCurrent("", typ.Interface.Package.FullName())
 			}
 
+			c.addKindImports(imports, params.Kind, []golang.Type{cType}, false, true)
+
 			stub := &Stub{
 				Filename: stubFilename,
 				Package:  pkg,
@@ -196,7 +244,10 @@ func (c *Collector) collectMethodStubs(
 				},
 				GenTypes:      false,
 				GenMethods:    true,
+				MethodTpl:     params.Kind.MethodTpl,
 				MethodBodyTpl: params.MethodBodyTpl,
+				CtxAware:      params.CtxAware,
+				PerMethod:     true,
 			}
 
 			stubs = append(stubs, stub)
@@ -205,3 +256,35 @@ func (c *Collector) collectMethodStubs(
 
 	return stubs, nil
 }
+
+func (c *Collector) addKindImports(
+	imports *goimports.ImportGroups,
+	kind *Kind,
+	types []golang.Type,
+	forTypes bool,
+	forMethods bool,
+) {
+	if forTypes {
+		for _, path := range kind.TypeImports {
+			imports.Add("", path)
+		}
+
+		if kind.WrapsInterface {
+			for _, typ := range types {
+				if !typ.Package.Equal(typ.Interface.Package) {
+					imports.AddCurrent("", typ.Interface.Package.FullName())
+				}
+			}
+		}
+	}
+
+	if forMethods && kind.MethodImports != nil {
+		for _, typ := range types {
+			for _, method := range typ.Methods {
+				for _, path := range kind.MethodImports(method) {
+					imports.Add("", path)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package stub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/artarts36/gostub/internal/golang"
+)
+
+const (
+	KindStub          = "stub"
+	KindSingleflight  = "singleflight"
+	KindFallback      = "fallback"
+	KindFanOut        = "fanout"
+	KindLazy          = "lazy"
+	KindGuarded       = "guarded"
+	KindUnimplemented = "unimplemented"
+)
+
+type Kind struct {
+	Name string
+
+	TypesTpl  string
+	MethodTpl string
+	ErrorsTpl string
+
+	DefaultTypeName string
+	DefaultFilename string
+	FileSuffix      string
+
+	WrapsInterface bool
+	TypeImports    []string
+	ErrorsImports  []string
+	MethodImports  func(method *golang.GoMethod) []string
+}
+
+var kinds = map[string]*Kind{
+	KindStub: {
+		Name:            KindStub,
+		TypesTpl:        "stub_types.tpl",
+		MethodTpl:       "method.tpl",
+		ErrorsTpl:       "stub_errors.tpl",
+		DefaultTypeName: "Stub{{ .Interface.Name.Pascal.Value }}",
+		DefaultFilename: "stubs.go",
+		FileSuffix:      "stub",
+		ErrorsImports:   []string{"errors"},
+	},
+	KindSingleflight: {
+		Name:            KindSingleflight,
+		TypesTpl:        "singleflight_types.tpl",
+		MethodTpl:       "singleflight_method.tpl",
+		DefaultTypeName: "Singleflight{{ .Interface.Name.Pascal.Value }}",
+		DefaultFilename: "singleflight.go",
+		FileSuffix:      "singleflight",
+		WrapsInterface:  true,
+		TypeImports:     []string{"fmt", "sync"},
+	},
+	KindFallback: {
+		Name:            KindFallback,
+		TypesTpl:        "fallback_types.tpl",
+		MethodTpl:       "fallback_method.tpl",
+		DefaultTypeName: "Fallback{{ .Interface.Name.Pascal.Value }}",
+		DefaultFilename: "fallback.go",
+		FileSuffix:      "fallback",
+		WrapsInterface:  true,
+		MethodImports: func(method *golang.GoMethod) []string {
+			if method.ReturnsError() {
+				return []string{"errors"}
+			}
+
+			return nil
+		},
+	},
+	KindFanOut: {
+		Name:            KindFanOut,
+		TypesTpl:        "fanout_types.tpl",
+		MethodTpl:       "fanout_method.tpl",
+		DefaultTypeName: "FanOut{{ .Interface.Name.Pascal.Value }}",
+		DefaultFilename: "fanout.go",
+		FileSuffix:      "fanout",
+		WrapsInterface:  true,
+		MethodImports: func(method *golang.GoMethod) []string {
+			if method.ReturnsError() {
+				return []string{"errors"}
+			}
+
+			return nil
+		},
+	},
+	KindLazy: {
+		Name:            KindLazy,
+		TypesTpl:        "lazy_types.tpl",
+		MethodTpl:       "lazy_method.tpl",
+		DefaultTypeName: "Lazy{{ .Interface.Name.Pascal.Value }}",
+		DefaultFilename: "lazy.go",
+		FileSuffix:      "lazy",
+		WrapsInterface:  true,
+		TypeImports:     []string{"sync"},
+	},
+	KindGuarded: {
+		Name:            KindGuarded,
+		TypesTpl:        "guarded_types.tpl",
+		MethodTpl:       "guarded_method.tpl",
+		DefaultTypeName: "Guarded{{ .Interface.Name.Pascal.Value }}",
+		DefaultFilename: "guarded.go",
+		FileSuffix:      "guarded",
+		WrapsInterface:  true,
+		TypeImports:     []string{"context"},
+		MethodImports: func(method *golang.GoMethod) []string {
+			if method.Parameters.Context() == "" {
+				return []string{"context"}
+			}
+
+			return nil
+		},
+	},
+	KindUnimplemented: {
+		Name:            KindUnimplemented,
+		TypesTpl:        "unimplemented_types.tpl",
+		MethodTpl:       "unimplemented_method.tpl",
+		DefaultTypeName: "Unimplemented{{ .Interface.Name.Pascal.Value }}",
+		DefaultFilename: "unimplemented.go",
+		FileSuffix:      "unimplemented",
+		TypeImports:     []string{"errors"},
+		MethodImports: func(method *golang.GoMethod) []string {
+			if method.ReturnsError() {
+				return []string{"fmt"}
+			}
+
+			return nil
+		},
+	},
+}
+
+func FindKind(name string) (*Kind, error) {
+	if name == "" {
+		name = KindStub
+	}
+
+	kind, ok := kinds[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q, available: %s", name, strings.Join(KindNames(), ", "))
+	}
+
+	return kind, nil
+}
+
+func KindNames() []string {
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -1,12 +1,15 @@
 package stub
 
 import (
+	"strings"
+
 	"github.com/artarts36/goimports"
 	"github.com/artarts36/gomodfinder"
 	"github.com/artarts36/gostub/internal/golang"
 )
 
 type Stub struct {
+	Header   string
 	Filename string
 	Package  *gomodfinder.Package
 	Imports  *goimports.ImportGroups
@@ -14,5 +17,36 @@ type Stub struct {
 
 	GenMethods    bool
 	GenTypes      bool
+	TypesTpl      string
+	ErrorsTpl     string
+	MethodTpl     string
 	MethodBodyTpl string
+	CtxAware      bool
+
+	// PerMethod is set for files with single method, which can be scaffolds for editing.
+	PerMethod bool
+}
+
+// ImportCandidates returns imports which generated code may use: imports of source files and source packages.
+func (s *Stub) ImportCandidates() []*goimports.ImportGroups {
+	sourcePackages := goimports.NewImportGroups("")
+
+	candidates := []*goimports.ImportGroups{s.Imports, sourcePackages}
+
+	for _, typ := range s.Types {
+		candidates = append(candidates, typ.Interface.Imports)
+
+		for _, method := range typ.Methods {
+			candidates = append(candidates, method.Imports)
+		}
+
+		alias := ""
+		if path := typ.Interface.Package.FullName(); !strings.HasSuffix(path, "/"+typ.Interface.Package.Name) {
+			alias = typ.Interface.Package.Name
+		}
+
+		sourcePackages.AddCurrent(alias, typ.Interface.Package.FullName())
+	}
+
+	return candidates
 }
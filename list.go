@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func listCmd() *command {
+	return &command{
+		Name:        "list",
+		Description: "print interfaces and methods of source or of sources of config jobs",
+		Args:        []*cli.ArgDefinition{sourceArg()},
+		Opts: []*cli.OptDefinition{
+			{
+				Name:      "interfaces",
+				WithValue: true,
+			},
+		},
+		Action: list,
+	}
+}
+
+func list(ctx *cli.Context) error {
+	return runJobs(ctx, func(command *cmd.Command, params *cmd.Params) error {
+		return command.List(ctx.Context, params)
+	})
+}
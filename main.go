@@ -6,159 +6,273 @@ import (
 	"github.com/artarts36/gomodfinder"
 	"github.com/artarts36/gostub/internal/cmd"
 	"github.com/artarts36/gostub/internal/renderer"
+	"github.com/artarts36/gostub/internal/stub"
 	cli "github.com/artarts36/singlecli"
 	"log/slog"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 const (
-	defaultFilename          = "stubs.go"
-	defaultFilenamePerMethod = "{{ .Interface.Name.Snake.Value }}_{{ .Method.Name.Snake.Value }}_stub.go"
-	defaultFilenamePerType   = "{{ .Interface.Name.Snake.Value }}_stub.go"
-
-	defaultTypeName = "Stub{{ .Interface.Name.Pascal.Value }}"
+	defaultFilenamePerMethod = "{{ .Interface.Name.Snake.Value }}_{{ .Method.Name.Snake.Value }}_%s.go"
+	defaultFilenamePerType   = "{{ .Interface.Name.Snake.Value }}_%s.go"
 )
 
+// Version is set on build: go build -ldflags "-X main.Version=v1.0.0".
+var Version = "dev"
+
 func main() {
+	if len(os.Args) > 1 {
+		if c := findCommand(os.Args[1]); c != nil {
+			c.app().Run(context.Background(), c.args(os.Args))
+
+			return
+		}
+	}
+
 	application := &cli.App{
 		BuildInfo: &cli.BuildInfo{
-			Name: "gostub",
-		},
-		Args: []*cli.ArgDefinition{
-			{
-				Name:        "source",
-				Required:    true,
-				Description: "path to source .go file",
-			},
-		},
-		Opts: []*cli.OptDefinition{
-			{
-				Name:        "skip-exists",
-				Description: "skip exists files",
-			},
-			{
-				Name:        "method-body",
-				Description: "method-body: nil-returns, panic",
-				WithValue:   true,
-			},
-			{
-				Name:      "package",
-				WithValue: true,
-			},
-			{
-				Name:      "filename",
-				WithValue: true,
-			},
-			{
-				Name:        "per-method",
-				Description: "generate stub file per method",
-			},
-			{
-				Name:      "per-method-filename",
-				WithValue: true,
-			},
-			{
-				Name:        "per-type",
-				Description: "generate stub file per interface",
-			},
-			{
-				Name:      "per-type-filename",
-				WithValue: true,
-			},
-			{
-				Name:      "type-name",
-				WithValue: true,
-			},
-			{
-				Name:      "out",
-				WithValue: true,
-			},
-			{
-				Name:      "interfaces",
-				WithValue: true,
-			},
-		},
-		Action: run,
+			Name:    "gostub",
+			Version: Version,
+		},
+		Args:          generateArgs(),
+		Opts:          generateOpts(),
+		Action:        run,
+		UsageExamples: usageExamples(),
 	}
 
 	application.RunWithGlobalArgs(context.Background())
 }
 
+func generateArgs() []*cli.ArgDefinition {
+	return []*cli.ArgDefinition{
+		{
+			Name:        "source",
+			Required:    true,
+			Description: "path to source .go file",
+		},
+	}
+}
+
+// generateOpts returns options of generation, shared by commands which need to build stubs.
+func generateOpts() []*cli.OptDefinition {
+	return []*cli.OptDefinition{
+		{
+			Name:        "skip-exists",
+			Description: "skip exists files",
+		},
+		{
+			Name:        "update",
+			Description: "add missing methods and types to existing files, keeping existing code",
+		},
+		{
+			Name:        "dry-run",
+			Description: "print files which would be created, changed or kept, without writing",
+		},
+		{
+			Name:        "diff",
+			Description: "print unified diff of generated code with files on disk, without writing",
+		},
+		{
+			Name:        "check",
+			Description: "fail when files on disk differ from generated code, without writing",
+		},
+		{
+			Name:        "kind",
+			Description: fmt.Sprintf("kind of generated type: %s", strings.Join(stub.KindNames(), ", ")),
+			WithValue:   true,
+		},
+		{
+			Name:        "method-body",
+			Description: fmt.Sprintf("method-body: %s, file:<path>", strings.Join(stub.MethodBodyNames(), ", ")),
+			WithValue:   true,
+		},
+		{
+			Name:        "ctx-aware",
+			Description: "return ctx.Err() from stub methods when context is already cancelled",
+		},
+		{
+			Name:      "package",
+			WithValue: true,
+		},
+		{
+			Name:      "filename",
+			WithValue: true,
+		},
+		{
+			Name:        "per-method",
+			Description: "generate stub file per method",
+		},
+		{
+			Name:      "per-method-filename",
+			WithValue: true,
+		},
+		{
+			Name:        "editable-per-method",
+			Description: "generate per method files without \"DO NOT EDIT\" header, as scaffolds for editing",
+		},
+		{
+			Name:        "per-type",
+			Description: "generate stub file per interface",
+		},
+		{
+			Name:      "per-type-filename",
+			WithValue: true,
+		},
+		{
+			Name:      "type-name",
+			WithValue: true,
+		},
+		{
+			Name:        "templates",
+			Description: "path to directory with templates overriding embedded ones",
+			WithValue:   true,
+		},
+		{
+			Name:      "out",
+			WithValue: true,
+		},
+		{
+			Name:      "interfaces",
+			WithValue: true,
+		},
+	}
+}
+
 func run(ctx *cli.Context) error {
-	rend, err := renderer.NewRenderer()
+	command, params, err := prepare(ctx.Context, ctx.GetArg("source"), ctx.Opts)
+	if err != nil {
+		return err
+	}
+
+	return command.Run(ctx.Context, params)
+}
+
+// prepare creates command and its params from source and options, relative paths are resolved from working directory.
+func prepare(ctx context.Context, source string, opts map[string]string) (*cmd.Command, *cmd.Params, error) {
+	rend, err := renderer.NewRenderer(opts["templates"])
 	if err != nil {
-		return fmt.Errorf("failed to create renderer: %w", err)
+		return nil, nil, fmt.Errorf("failed to create renderer: %w", err)
 	}
 
 	command := cmd.NewCommand(rend)
 
-	filename := ctx.Opts["filename"]
+	kind, err := stub.FindKind(opts["kind"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	methodBody, err := stub.FindMethodBody(opts["method-body"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filename := opts["filename"]
 	if filename == "" {
-		filename = defaultFilename
+		filename = kind.DefaultFilename
 	}
 
-	perMethodFilename := ctx.Opts["per-method-filename"]
+	perMethodFilename := opts["per-method-filename"]
 	if perMethodFilename == "" {
-		perMethodFilename = defaultFilenamePerMethod
+		perMethodFilename = fmt.Sprintf(defaultFilenamePerMethod, kind.FileSuffix)
 	}
 
-	perTypeFilename := ctx.Opts["per-type-filename"]
+	perTypeFilename := opts["per-type-filename"]
 	if perTypeFilename == "" {
-		perTypeFilename = defaultFilenamePerType
+		perTypeFilename = fmt.Sprintf(defaultFilenamePerType, kind.FileSuffix)
 	}
 
-	typeName := ctx.Opts["type-name"]
+	typeName := opts["type-name"]
 	if typeName == "" {
-		typeName = defaultTypeName
+		typeName = kind.DefaultTypeName
 	}
 
-	interfacesString := ctx.Opts["interfaces"]
-	interfaces := []string{}
-	if interfacesString != "" {
-		interfaces = strings.Split(interfacesString, ",")
-		for i, s := range interfaces {
-			interfaces[i] = strings.Trim(s, " ")
-		}
-	}
+	interfaces := splitInterfaces(opts["interfaces"])
 
-	sourceGoModule, err := findGoModule(filepath.Dir(ctx.GetArg("source")))
+	sourceGoModule, err := findGoModule(filepath.Dir(source))
 	if err != nil {
-		return fmt.Errorf("failed to find source go.mod file: %w", err)
+		return nil, nil, fmt.Errorf("failed to find source go.mod file: %w", err)
 	}
 
-	slog.InfoContext(ctx.Context, "[main] source go.mod found", slog.String("go_mod", sourceGoModule.Path))
+	slog.InfoContext(ctx, "[main] source go.mod found", slog.String("go_mod", sourceGoModule.Path))
 
 	targetGoModule, err := findCurrentGoModule()
 	if err != nil {
-		return fmt.Errorf("failed to find current go.mod file: %w", err)
+		return nil, nil, fmt.Errorf("failed to find current go.mod file: %w", err)
 	}
 
-	slog.InfoContext(ctx.Context, "[main] target go.mod found", slog.String("go_mod", targetGoModule.Path))
+	slog.InfoContext(ctx, "[main] target go.mod found", slog.String("go_mod", targetGoModule.Path))
+
+	invocation, err := cmd.NewInvocation(filepath.Dir(targetGoModule.Path), source, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build invocation: %w", err)
+	}
 
-	return command.Run(ctx.Context, &cmd.Params{
-		Source: ctx.GetArg("source"),
+	return command, &cmd.Params{
+		Source: source,
 
-		MethodBody: ctx.Opts["method-body"],
-		Package:    ctx.Opts["package"],
+		Kind:       kind,
+		MethodBody: methodBody,
+		CtxAware:   hasOpt(opts, "ctx-aware"),
+		Package:    opts["package"],
 
 		Filename: filename,
 
-		MethodPerFile:     ctx.HasOpt("per-method"),
+		MethodPerFile:     hasOpt(opts, "per-method"),
 		PerMethodFilename: perMethodFilename,
 
-		TypePerFile:     ctx.HasOpt("per-type"),
+		TypePerFile:     hasOpt(opts, "per-type"),
 		PerTypeFilename: perTypeFilename,
 
 		TypeName: typeName,
 
-		Out:        ctx.Opts["out"],
+		Out:        opts["out"],
 		Interfaces: interfaces,
-		SkipExists: ctx.HasOpt("skip-exists"),
+		SkipExists: hasOpt(opts, "skip-exists"),
+		Update:     hasOpt(opts, "update"),
+		DryRun:     hasOpt(opts, "dry-run"),
+		Diff:       hasOpt(opts, "diff"),
+		Check:      hasOpt(opts, "check"),
+
+		Version:           Version,
+		Invocation:        invocation,
+		EditablePerMethod: hasOpt(opts, "editable-per-method"),
 
 		SourceGoModule: sourceGoModule,
 		TargetGoModule: targetGoModule,
-	})
+	}, nil
+}
+
+// splitInterfaces returns names of interfaces of comma-separated list.
+func splitInterfaces(value string) []string {
+	interfaces := []string{}
+	if value != "" {
+		interfaces = strings.Split(value, ",")
+		for i, s := range interfaces {
+			interfaces[i] = strings.Trim(s, " ")
+		}
+	}
+
+	return interfaces
+}
+
+// hasOpt returns whether flag is enabled. Flag is disabled by explicit false value, e.g. --per-method=false,
+// which overrides flag enabled in config.
+func hasOpt(opts map[string]string, name string) bool {
+	value, ok := opts[name]
+	if !ok {
+		return false
+	}
+
+	if value == "" {
+		return true
+	}
+
+	enabled, err := strconv.ParseBool(value)
+
+	return err != nil || enabled
 }
 
 func findCurrentGoModule() (*gomodfinder.ModFile, error) {
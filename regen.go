@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func regenCmd() *command {
+	return &command{
+		Name:        "regen",
+		Description: "regenerate files by invocations from their headers",
+		Args:        []*cli.ArgDefinition{pathArg(true)},
+		Action:      regen,
+	}
+}
+
+func regen(ctx *cli.Context) error {
+	return runGenerated(ctx, func(command *cmd.Command, params *cmd.Params) error {
+		params.KeepEditable = true
+
+		return command.Run(ctx.Context, params)
+	})
+}
+
+// runGenerated runs action for invocations of generated files found by path argument.
+func runGenerated(ctx *cli.Context, action func(command *cmd.Command, params *cmd.Params) error) error {
+	groups, err := cmd.FindGenerated(ctx.GetArg("path"))
+	if err != nil {
+		return err
+	}
+
+	if len(groups) == 0 {
+		slog.WarnContext(ctx.Context, "[main] generated files not found", slog.String("path", ctx.GetArg("path")))
+
+		return nil
+	}
+
+	jobs := make([]*job, 0, len(groups))
+
+	for _, group := range groups {
+		// invocation paths are relative to root of target module
+		jobs = append(jobs, &job{
+			Title:  group.Invocation.String(),
+			Dir:    group.ModuleDir,
+			Source: group.Invocation.Source,
+			Opts:   group.Invocation.Opts,
+		})
+	}
+
+	return runJobList(ctx, jobs, action)
+}
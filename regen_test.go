@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func TestRegenRunsInvocationsOfHeaders(t *testing.T) {
+	manifest := newTestModule(t, nil, map[string]string{
+		"contracts/contracts.go": testContracts,
+	})
+
+	captureStdout(t)
+
+	err := generate(&cli.Context{
+		Context: context.Background(),
+		Args:    map[string]string{"source": "./contracts/contracts.go"},
+		Opts: map[string]string{
+			"out":       "./stubs",
+			"type-name": "Fake{{ .Interface.Name.Pascal.Value }}",
+		},
+	})
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(manifest.Dir, "contracts", "contracts.go"), `package contracts
+
+import "context"
+
+type Users interface {
+	Get(ctx context.Context, id int) (string, error)
+	Delete(ctx context.Context, id int) error
+}
+`)
+
+	pathCtx := &cli.Context{
+		Context: context.Background(),
+		Args:    map[string]string{"path": "./..."},
+		Opts:    map[string]string{},
+	}
+
+	err = check(pathCtx)
+	if !errors.Is(err, cmd.ErrStale) {
+		t.Fatalf("check() of changed contracts error = %v, want %v", err, cmd.ErrStale)
+	}
+
+	err = regen(pathCtx)
+	if err != nil {
+		t.Fatalf("regen() error = %v", err)
+	}
+
+	stubs, err := os.ReadFile(filepath.Join(manifest.Dir, "stubs", "stubs.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(stubs), "*FakeUsers) Delete(") {
+		t.Errorf("file isn't regenerated with options of header:\n%s", stubs)
+	}
+
+	err = check(pathCtx)
+	if err != nil {
+		t.Errorf("check() of regenerated files error = %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func statusCmd() *command {
+	return &command{
+		Name: "status",
+		Description: fmt.Sprintf(
+			"print status of generated files recorded in %s: ok, modified, missing, stale; "+
+				"fails when files are modified or missing",
+			cmd.ManifestFilename,
+		),
+		Action: status,
+	}
+}
+
+func status(_ *cli.Context) error {
+	manifest, err := loadManifest()
+	if err != nil {
+		return err
+	}
+
+	drifted := false
+
+	for _, file := range manifest.Files {
+		fileStatus, statusErr := manifest.Status(file)
+		if statusErr != nil {
+			return statusErr
+		}
+
+		// editable files are expected to be modified
+		if fileStatus == cmd.FileStatusModified && file.Editable {
+			fileStatus = cmd.FileStatusOK
+		}
+
+		if fileStatus == cmd.FileStatusModified || fileStatus == cmd.FileStatusMissing {
+			drifted = true
+		}
+
+		_, err = fmt.Fprintf(stdout, "%-8s %s\n", fileStatus, file.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if drifted {
+		return cmd.ErrModified
+	}
+
+	return nil
+}
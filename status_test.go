@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/artarts36/gostub/internal/cmd"
+)
+
+func TestStatus(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   map[string]*cmd.ManifestFile
+		out     string
+		wantErr error
+	}{
+		{
+			name: "up-to-date",
+			files: map[string]*cmd.ManifestFile{
+				"out/a.go":      {},
+				"out/stale.go":  {Stale: true},
+				"out/edited.go": {Editable: true, Hash: "sha256:old"},
+			},
+			out: "ok       out/a.go\nok       out/edited.go\nstale    out/stale.go\n",
+		},
+		{
+			name: "modified",
+			files: map[string]*cmd.ManifestFile{
+				"out/a.go": {Hash: "sha256:old"},
+			},
+			out:     "modified out/a.go\n",
+			wantErr: cmd.ErrModified,
+		},
+		{
+			name: "missing",
+			files: map[string]*cmd.ManifestFile{
+				"out/missing.go": {},
+			},
+			out:     "missing  out/missing.go\n",
+			wantErr: cmd.ErrModified,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			contents := map[string]string{}
+			for path := range c.files {
+				if path != "out/missing.go" {
+					contents[path] = "package out\n"
+				}
+			}
+
+			newTestModule(t, c.files, contents)
+
+			out := captureStdout(t)
+
+			err := status(newTestContext(map[string]string{}))
+			if !errors.Is(err, c.wantErr) {
+				t.Errorf("status() error = %v, want %v", err, c.wantErr)
+			}
+
+			if out.String() != c.out {
+				t.Errorf("status() printed\n%s\nwant\n%s", out.String(), c.out)
+			}
+		})
+	}
+}
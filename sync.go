@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/artarts36/gostub/internal/cmd"
+	cli "github.com/artarts36/singlecli"
+)
+
+func syncCmd() *command {
+	return &command{
+		Name:        "sync",
+		Description: "fix signatures of methods in existing files, report methods missing in interfaces and fail when they are found",
+		Args:        []*cli.ArgDefinition{sourceArg()},
+		Opts:        generateOpts(),
+		Action:      runSync,
+	}
+}
+
+func runSync(ctx *cli.Context) error {
+	return runJobs(ctx, func(command *cmd.Command, params *cmd.Params) error {
+		return command.Sync(ctx.Context, params)
+	})
+}
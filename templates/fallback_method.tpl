@@ -0,0 +1,13 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ $r := $typ.Receiver }}{{ $args := $method.Parameters.CallArgs }}{{ include "method_signature.tpl" "Type" $typ "Method" $method }} {
+{{ if $method.ReturnsError }}{{ $err := $method.LocalName "err" }}{{ $secondaryErr := $method.LocalName "secondaryErr" }}    {{ $method.ResultVars $err }} := {{ $r }}.primary.{{ $method.Name.Value }}({{ $args }})
+    if {{ $err }} == nil {
+        return {{ $method.ResultVars "nil" }}
+    }
+
+    {{ $method.ResultVars $secondaryErr }} := {{ $r }}.secondary.{{ $method.Name.Value }}({{ $args }})
+    if {{ $secondaryErr }} != nil {
+        return {{ $method.ResultVars (printf "errors.Join(%s, %s)" $err $secondaryErr) }}
+    }
+
+    return {{ $method.ResultVars "nil" }}{{ else }}    {{ if noEmpty $method.Results.List }}return {{ end }}{{ $r }}.primary.{{ $method.Name.Value }}({{ $args }}){{ end }}
+}
\ No newline at end of file
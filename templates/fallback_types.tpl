@@ -0,0 +1,13 @@
+{{ $types := .Types }}{{ range $typIndex, $typ := $types }}
+type {{ $typ.Name }} struct {
+    primary   {{ $typ.InterfaceName }}
+    secondary {{ $typ.InterfaceName }}
+}
+
+func New{{ $typ.Name }}(primary, secondary {{ $typ.InterfaceName }}) *{{ $typ.Name }} {
+    return &{{ $typ.Name }}{
+        primary:   primary,
+        secondary: secondary,
+    }
+}{{ if hasNext $typIndex $types }}
+{{ end }}{{ end }}
\ No newline at end of file
@@ -0,0 +1,32 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ $r := $typ.Receiver }}{{ $args := $method.Parameters.CallArgs }}{{ $results := $method.Results.List }}{{ $impl := $method.LocalName "impl" }}{{ $err := $method.LocalName "err" }}{{ $errs := $method.LocalName "errs" }}{{ $found := $method.LocalName "found" }}{{ include "method_signature.tpl" "Type" $typ "Method" $method }} {
+{{ if $method.ReturnsOnlyError }}    {{ $errs }} := make([]error, 0, len({{ $r }}.impls))
+    for _, {{ $impl }} := range {{ $r }}.impls {
+        if {{ $err }} := {{ $impl }}.{{ $method.Name.Value }}({{ $args }}); {{ $err }} != nil {
+            {{ $errs }} = append({{ $errs }}, {{ $err }})
+        }
+    }
+
+    return errors.Join({{ $errs }}...){{ else if noEmpty $results }}    var (
+{{ range $i, $result := $results }}{{ if not (and (isLast $i $results) $method.ReturnsError) }}        {{ $method.LocalName (printf "r%d" $i) }} {{ typeOf $result $typ.Package }}
+{{ end }}{{ end }}        {{ $found }} bool{{ if $method.ReturnsError }}
+        {{ $errs }} []error{{ end }}
+    )
+
+    // results of the first {{ if $method.ReturnsError }}succeeded {{ end }}implementation are returned, all implementations are called
+    for _, {{ $impl }} := range {{ $r }}.impls {
+        {{ range $i, $result := $results }}{{ if $i }}, {{ end }}{{ if and (isLast $i $results) $method.ReturnsError }}{{ $err }}{{ else }}{{ $method.LocalName (printf "v%d" $i) }}{{ end }}{{ end }} := {{ $impl }}.{{ $method.Name.Value }}({{ $args }}){{ if $method.ReturnsError }}
+        if {{ $err }} != nil {
+            {{ $errs }} = append({{ $errs }}, {{ $err }})
+
+            continue
+        }
+{{ end }}
+        if !{{ $found }} {
+            {{ range $i, $result := $results }}{{ if not (and (isLast $i $results) $method.ReturnsError) }}{{ $method.LocalName (printf "r%d" $i) }}, {{ end }}{{ end }}{{ $found }} = {{ range $i, $result := $results }}{{ if not (and (isLast $i $results) $method.ReturnsError) }}{{ $method.LocalName (printf "v%d" $i) }}, {{ end }}{{ end }}true
+        }
+    }
+
+    return {{ range $i, $result := $results }}{{ if $i }}, {{ end }}{{ if and (isLast $i $results) $method.ReturnsError }}errors.Join({{ $errs }}...){{ else }}{{ $method.LocalName (printf "r%d" $i) }}{{ end }}{{ end }}{{ else }}    for _, {{ $impl }} := range {{ $r }}.impls {
+        {{ $impl }}.{{ $method.Name.Value }}({{ $args }})
+    }{{ end }}
+}
\ No newline at end of file
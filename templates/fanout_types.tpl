@@ -0,0 +1,11 @@
+{{ $types := .Types }}{{ range $typIndex, $typ := $types }}
+type {{ $typ.Name }} struct {
+    impls []{{ $typ.InterfaceName }}
+}
+
+func New{{ $typ.Name }}(impls ...{{ $typ.InterfaceName }}) *{{ $typ.Name }} {
+    return &{{ $typ.Name }}{
+        impls: impls,
+    }
+}{{ if hasNext $typIndex $types }}
+{{ end }}{{ end }}
\ No newline at end of file
@@ -0,0 +1,7 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ $r := $typ.Receiver }}{{ $policy := or ($method.Directives.Get "policy") (printf "%s.%s" $typ.Interface.Name.Value $method.Name.Value) }}{{ $ctx := or $method.Parameters.Context "context.Background()" }}{{ include "method_signature.tpl" "Type" $typ "Method" $method }} {
+    if err := {{ $r }}.policy.Authorize({{ $ctx }}, {{ quote $policy }}{{ with $method.Parameters.NonContext.CallArgs }}, {{ . }}{{ end }}); err != nil {
+{{ if $method.ReturnsError }}        return {{ include "zero_results.tpl" "Type" $typ "Method" $method "Err" "err" }}{{ else }}        panic("{{ $typ.Name }}.{{ $method.Name.Value }}: " + err.Error()){{ end }}
+    }
+
+    {{ if noEmpty $method.Results.List }}return {{ end }}{{ $r }}.next.{{ $method.Name.Value }}({{ $method.Parameters.CallArgs }})
+}
\ No newline at end of file
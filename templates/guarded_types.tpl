@@ -0,0 +1,17 @@
+{{ $types := .Types }}{{ range $typIndex, $typ := $types }}
+type {{ $typ.Name }}Policy interface {
+    Authorize(ctx context.Context, method string, args ...any) error
+}
+
+type {{ $typ.Name }} struct {
+    next   {{ $typ.InterfaceName }}
+    policy {{ $typ.Name }}Policy
+}
+
+func New{{ $typ.Name }}(next {{ $typ.InterfaceName }}, policy {{ $typ.Name }}Policy) *{{ $typ.Name }} {
+    return &{{ $typ.Name }}{
+        next:   next,
+        policy: policy,
+    }
+}{{ if hasNext $typIndex $types }}
+{{ end }}{{ end }}
\ No newline at end of file
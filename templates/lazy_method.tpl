@@ -0,0 +1,8 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ $r := $typ.Receiver }}{{ $results := $method.Results.List }}{{ $impl := $method.LocalName "impl" }}{{ $err := $method.LocalName "err" }}{{ include "method_signature.tpl" "Type" $typ "Method" $method }} {
+    {{ $impl }}, {{ $err }} := {{ $r }}.instance()
+    if {{ $err }} != nil {
+{{ if $method.ReturnsError }}        return {{ include "zero_results.tpl" "Type" $typ "Method" $method "Err" $err }}{{ else }}        panic("{{ $typ.Name }}.{{ $method.Name.Value }}: failed to create {{ $typ.Interface.Name.Value }}: " + {{ $err }}.Error()){{ end }}
+    }
+
+    {{ if noEmpty $results }}return {{ end }}{{ $impl }}.{{ $method.Name.Value }}({{ $method.Parameters.CallArgs }})
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+{{ $types := .Types }}{{ range $typIndex, $typ := $types }}{{ $r := $typ.Receiver }}
+type {{ $typ.Name }} struct {
+    factory func() ({{ $typ.InterfaceName }}, error)
+
+    once sync.Once
+    impl {{ $typ.InterfaceName }}
+    err  error
+}
+
+func New{{ $typ.Name }}(factory func() ({{ $typ.InterfaceName }}, error)) *{{ $typ.Name }} {
+    return &{{ $typ.Name }}{
+        factory: factory,
+    }
+}
+
+func ({{ $r }} *{{ $typ.Name }}) instance() ({{ $typ.InterfaceName }}, error) {
+    {{ $r }}.once.Do(func() {
+        {{ $r }}.impl, {{ $r }}.err = {{ $r }}.factory()
+    })
+
+    return {{ $r }}.impl, {{ $r }}.err
+}{{ if hasNext $typIndex $types }}
+{{ end }}{{ end }}
\ No newline at end of file
@@ -1,3 +1,3 @@
-{{ $methodBodyTpl := .MethodBodyTpl }}{{ $typ := .Type }}{{ $method := .Method }}func ({{ $typ.Receiver }} *{{ $typ.Name }}) {{ $method.Name.Value }}({{ range $paramIndex, $param := $method.Parameters.List }}{{ $param.Name }} {{ .Type.Call $typ.Package }}{{ if (isLast $paramIndex $method.Parameters.List) }}{{ else }}, {{ end }}{{ end }}) {{ if isMany $method.Results.List }}({{ end }}{{ range $resultIndex, $result := $method.Results.List }}{{ if ne $result.Name "" }}{{ $result.Name }} {{ end }}{{ $result.Type.Call $typ.Package }}{{ if (isLast $resultIndex $method.Results.List) }}{{ else }}, {{ end }}{{ end }}{{ if isMany $method.Results.List }}){{ end }} {
-{{ include $methodBodyTpl "Type" $typ "Method" $method }}
+{{ $typ := .Type }}{{ $method := .Method }}{{ include "method_signature.tpl" "Type" $typ "Method" $method }} {
+{{ include .MethodBodyTpl "Type" $typ "Method" $method "CtxAware" .CtxAware }}
 }
\ No newline at end of file
@@ -0,0 +1,5 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ with $method.Parameters.LeadingContext }}{{ if $method.ReturnsError }}    if err := {{ . }}.Err(); err != nil {
+        return {{ include "zero_results.tpl" "Type" $typ "Method" $method "Err" "err" }}
+    }
+
+{{ end }}{{ end }}
\ No newline at end of file
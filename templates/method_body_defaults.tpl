@@ -0,0 +1 @@
+{{ $method := .Method }}{{ $typ := .Type }}{{ if .CtxAware }}{{ include "method_body_ctx_check.tpl" "Type" $typ "Method" $method }}{{ end }}    return {{ range $index, $result := $method.Results.List }}{{ .Type.DefaultFor $typ.Package }}{{ if (isLast $index $method.Results.List) }}{{ else }}, {{ end }}{{ end }}
\ No newline at end of file
@@ -1 +1 @@
-{{ $method := .Method }}{{ $typ := .Type }}    return {{ range $index, $result := $method.Results.List }}{{ .Type.ValueFor $typ.Package }}{{ if (isLast $index $method.Results.List) }}{{ else }}, {{ end }}{{ end }}
\ No newline at end of file
+{{ $method := .Method }}{{ $typ := .Type }}{{ if .CtxAware }}{{ include "method_body_ctx_check.tpl" "Type" $typ "Method" $method }}{{ end }}    return {{ range $index, $result := $method.Results.List }}{{ if .Type.IsError }}&NotImplementedError{Type: "{{ $typ.Name }}", Method: "{{ $method.Name.Value }}"}{{ else }}{{ .Type.ValueFor $typ.Package }}{{ end }}{{ if (isLast $index $method.Results.List) }}{{ else }}, {{ end }}{{ end }}
\ No newline at end of file
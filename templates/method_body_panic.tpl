@@ -1 +1 @@
-    panic("method {{ .Type.Name }}.{{ .Method.Name.Value }} not implemented")
\ No newline at end of file
+{{ if .CtxAware }}{{ include "method_body_ctx_check.tpl" "Type" .Type "Method" .Method }}{{ end }}    panic(&NotImplementedError{Type: "{{ .Type.Name }}", Method: "{{ .Method.Name.Value }}"})
\ No newline at end of file
@@ -0,0 +1 @@
+{{ $typ := .Type }}{{ $method := .Method }}func ({{ $typ.Receiver }} {{ if not .ValueReceiver }}*{{ end }}{{ $typ.Name }}) {{ $method.Name.Value }}({{ range $paramIndex, $param := $method.Parameters.List }}{{ $param.Name }} {{ .Type.Call $typ.Package }}{{ if (isLast $paramIndex $method.Parameters.List) }}{{ else }}, {{ end }}{{ end }}) {{ if isMany $method.Results.List }}({{ end }}{{ range $resultIndex, $result := $method.Results.List }}{{ if ne $result.Name "" }}{{ $result.Name }} {{ end }}{{ $result.Type.Call $typ.Package }}{{ if (isLast $resultIndex $method.Results.List) }}{{ else }}, {{ end }}{{ end }}{{ if isMany $method.Results.List }}){{ end }}
\ No newline at end of file
@@ -0,0 +1,15 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ $r := $typ.Receiver }}{{ $results := $method.Results.List }}{{ $resultType := printf "%s%sResult" (lowerFirst $typ.Name) $method.Name.Value }}{{ $result := $method.LocalName "result" }}{{ if noEmpty $results }}type {{ $resultType }} struct {
+{{ range $i, $res := $results }}    r{{ $i }} {{ $res.Type.Call $typ.Package }}
+{{ end }}}
+
+{{ end }}{{ include "method_signature.tpl" "Type" $typ "Method" $method }} {
+    {{ if noEmpty $results }}{{ $result }} := {{ end }}{{ $r }}.do({{ $r }}.keyFunc("{{ $method.Name.Value }}"{{ with $method.Parameters.NonContext.CallArgs }}, {{ . }}{{ end }}), func() any {
+        {{ if noEmpty $results }}{{ range $i, $res := $results }}{{ if $i }}, {{ end }}{{ $method.LocalName (printf "r%d" $i) }}{{ end }} := {{ end }}{{ $r }}.next.{{ $method.Name.Value }}({{ $method.Parameters.CallArgs }})
+
+        return {{ if noEmpty $results }}{{ $resultType }}{
+{{ range $i, $res := $results }}            r{{ $i }}: {{ $method.LocalName (printf "r%d" $i) }},
+{{ end }}        }{{ else }}nil{{ end }}
+    }){{ if noEmpty $results }}.({{ $resultType }})
+
+    return {{ range $i, $res := $results }}{{ if $i }}, {{ end }}{{ $result }}.r{{ $i }}{{ end }}{{ end }}
+}
\ No newline at end of file
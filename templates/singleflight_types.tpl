@@ -0,0 +1,70 @@
+{{ $types := .Types }}{{ range $typIndex, $typ := $types }}{{ $name := $typ.Name }}{{ $lname := lowerFirst $typ.Name }}{{ $r := $typ.Receiver }}
+type {{ $name }}KeyFunc func(method string, args ...any) string
+
+type {{ $name }} struct {
+    next    {{ $typ.InterfaceName }}
+    keyFunc {{ $name }}KeyFunc
+
+    mu    sync.Mutex
+    calls map[string]*{{ $lname }}Call
+}
+
+type {{ $lname }}Call struct {
+    wg    sync.WaitGroup
+    val   any
+    panic any
+}
+
+func New{{ $name }}(next {{ $typ.InterfaceName }}, keyFunc {{ $name }}KeyFunc) *{{ $name }} {
+    if keyFunc == nil {
+        keyFunc = {{ $lname }}Key
+    }
+
+    return &{{ $name }}{
+        next:    next,
+        keyFunc: keyFunc,
+        calls:   map[string]*{{ $lname }}Call{},
+    }
+}
+
+func {{ $lname }}Key(method string, args ...any) string {
+    // arguments are printed as Go values, so strings are quoted and can't be confused with other arguments
+    return fmt.Sprintf("%s%#v", method, args)
+}
+
+func ({{ $r }} *{{ $name }}) do(key string, fn func() any) any {
+    {{ $r }}.mu.Lock()
+    if call, ok := {{ $r }}.calls[key]; ok {
+        {{ $r }}.mu.Unlock()
+        call.wg.Wait()
+
+        if call.panic != nil {
+            panic(call.panic)
+        }
+
+        return call.val
+    }
+
+    call := &{{ $lname }}Call{}
+    call.wg.Add(1)
+    {{ $r }}.calls[key] = call
+    {{ $r }}.mu.Unlock()
+
+    defer func() {
+        call.panic = recover()
+        call.wg.Done()
+
+        {{ $r }}.mu.Lock()
+        delete({{ $r }}.calls, key)
+        {{ $r }}.mu.Unlock()
+
+        if call.panic != nil {
+            panic(call.panic)
+        }
+    }()
+
+    call.val = fn()
+
+    return call.val
+}{{ if hasNext $typIndex $types }}
+{{ end }}{{ end }}
\ No newline at end of file
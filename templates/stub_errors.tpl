@@ -0,0 +1,14 @@
+var ErrStubNotImplemented = errors.New("stub method not implemented")
+
+type NotImplementedError struct {
+    Type   string
+    Method string
+}
+
+func (e *NotImplementedError) Error() string {
+    return "method " + e.Type + "." + e.Method + " not implemented"
+}
+
+func (e *NotImplementedError) Unwrap() error {
+    return ErrStubNotImplemented
+}
\ No newline at end of file
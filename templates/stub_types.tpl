@@ -0,0 +1,10 @@
+{{ $types := .Types }}{{ range $typIndex, $typ := $types }}
+type {{ .Name }} struct {
+
+}{{ if (hasNext $typIndex $types) }}
+{{ end }}{{ end }}
+{{ range $typIndex, $typ := $types }}
+func New{{ .Name }}() *{{ .Name }} {
+    return &{{ .Name }}{}
+}{{ if hasNext $typIndex $types }}
+{{ end }}{{ end }}
\ No newline at end of file
@@ -0,0 +1,3 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ include "method_signature.tpl" "Type" $typ "Method" $method "ValueReceiver" true }} {
+{{ if noEmpty $method.Results.List }}    return {{ include "zero_results.tpl" "Type" $typ "Method" $method "Err" (printf "fmt.Errorf(\"%%w: %s.%s\", Err%s)" $typ.Interface.Name.Value $method.Name.Value $typ.Name) }}
+{{ end }}}
\ No newline at end of file
@@ -0,0 +1,7 @@
+{{ $types := .Types }}{{ range $typIndex, $typ := $types }}
+var Err{{ $typ.Name }} = errors.New("{{ $typ.Interface.Name.Value }} method is not implemented")
+
+type {{ $typ.Name }} struct{}
+
+func ({{ $typ.Name }}) mustEmbed{{ $typ.Name }}() {}{{ if hasNext $typIndex $types }}
+{{ end }}{{ end }}
\ No newline at end of file
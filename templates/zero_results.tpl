@@ -0,0 +1 @@
+{{ $typ := .Type }}{{ $method := .Method }}{{ $results := $method.Results.List }}{{ range $i, $result := $results }}{{ if $i }}, {{ end }}{{ if and (isLast $i $results) $method.ReturnsError }}{{ $.Err }}{{ else }}{{ zeroOf $result $typ.Package }}{{ end }}{{ end }}
\ No newline at end of file
@@ -0,0 +1,501 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artarts36/gostub/internal/cmd"
+	"github.com/artarts36/gostub/internal/golang"
+	cli "github.com/artarts36/singlecli"
+)
+
+const (
+	defaultWatchInterval = 500 * time.Millisecond
+	defaultWatchDebounce = 300 * time.Millisecond
+)
+
+func watchCmd() *command {
+	return &command{
+		Name: "watch",
+		Description: "regenerate code when sources, config or templates change, " +
+			"only changed files are rewritten",
+		Args: []*cli.ArgDefinition{sourceArg()},
+		Opts: append([]*cli.OptDefinition{
+			{
+				Name:        "interval",
+				Description: fmt.Sprintf("interval of polling files, %s by default", defaultWatchInterval),
+				WithValue:   true,
+			},
+			{
+				Name:        "debounce",
+				Description: fmt.Sprintf("time without changes before regeneration, %s by default", defaultWatchDebounce),
+				WithValue:   true,
+			},
+		}, watchOpts()...),
+		Action: watch,
+	}
+}
+
+// watchOpts returns options of generation, except options which print or check code without writing it.
+func watchOpts() []*cli.OptDefinition {
+	opts := make([]*cli.OptDefinition, 0)
+
+	for _, opt := range generateOpts() {
+		switch opt.Name {
+		case "dry-run", "diff", "check":
+			continue
+		}
+
+		opts = append(opts, opt)
+	}
+
+	return opts
+}
+
+// snapshot is modification time and size of watched files.
+type snapshot map[string]string
+
+func watch(ctx *cli.Context) error {
+	interval, err := durationOpt(ctx, "interval", defaultWatchInterval)
+	if err != nil {
+		return err
+	}
+
+	debounce, err := durationOpt(ctx, "debounce", defaultWatchDebounce)
+	if err != nil {
+		return err
+	}
+
+	runCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt)
+	defer stop()
+
+	jobs, err := loadWatchJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshots := make([]snapshot, len(jobs))
+	generated := make([]map[string]bool, len(jobs))
+	sources := make([]*sourceDecls, len(jobs))
+	configSnapshot := takeSnapshot(configFiles(ctx))
+
+	regenerate := func(indexes []int, changed map[int]map[string]bool) {
+		for _, i := range indexes {
+			sources[i] = regenerateChanges(ctx, jobs[i], changed[i], sources[i])
+			generated[i] = generatedFiles(jobs[i])
+			snapshots[i] = takeSnapshot(watchedFiles(jobs[i], generated[i]))
+		}
+	}
+
+	regenerate(jobIndexes(jobs), nil)
+
+	slog.InfoContext(runCtx, "[watch] watching for changes", slog.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	changes := newPendingChanges(debounce)
+
+	for {
+		select {
+		case <-runCtx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if current := takeSnapshot(configFiles(ctx)); !current.equal(configSnapshot) {
+			configSnapshot = current
+
+			reloaded, loadErr := loadWatchJobs(ctx)
+			if loadErr != nil {
+				slog.ErrorContext(runCtx, "[watch] failed to reload config", slog.Any("err", loadErr))
+
+				continue
+			}
+
+			jobs = reloaded
+			snapshots = make([]snapshot, len(jobs))
+			generated = make([]map[string]bool, len(jobs))
+			sources = make([]*sourceDecls, len(jobs))
+			changes = newPendingChanges(debounce)
+
+			regenerate(jobIndexes(jobs), nil)
+
+			continue
+		}
+
+		for i, j := range jobs {
+			current := takeSnapshot(watchedFiles(j, generated[i]))
+			if !current.equal(snapshots[i]) {
+				changes.add(i, current.changed(snapshots[i]), time.Now())
+				snapshots[i] = current
+			}
+		}
+
+		if indexes, changed := changes.release(time.Now()); len(indexes) > 0 {
+			regenerate(indexes, changed)
+		}
+	}
+}
+
+// pendingChanges collects changed files of jobs until files aren't changed during debounce.
+type pendingChanges struct {
+	debounce   time.Duration
+	files      map[int]map[string]bool
+	lastChange time.Time
+}
+
+func newPendingChanges(debounce time.Duration) *pendingChanges {
+	return &pendingChanges{
+		debounce: debounce,
+		files:    map[int]map[string]bool{},
+	}
+}
+
+func (c *pendingChanges) add(job int, files []string, now time.Time) {
+	if c.files[job] == nil {
+		c.files[job] = map[string]bool{}
+	}
+
+	for _, file := range files {
+		c.files[job][file] = true
+	}
+
+	c.lastChange = now
+}
+
+// release returns sorted indexes of changed jobs and their changed files, when debounce is passed after last change.
+// Released changes are removed.
+func (c *pendingChanges) release(now time.Time) ([]int, map[int]map[string]bool) {
+	if len(c.files) == 0 || now.Sub(c.lastChange) < c.debounce {
+		return nil, nil
+	}
+
+	indexes := make([]int, 0, len(c.files))
+	for i := range c.files {
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	files := c.files
+	c.files = map[int]map[string]bool{}
+
+	return indexes, files
+}
+
+func loadWatchJobs(ctx *cli.Context) ([]*job, error) {
+	jobs, err := loadJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// watched files are resolved from job directory, which must not depend on working directory
+	for _, j := range jobs {
+		delete(j.Opts, "interval")
+		delete(j.Opts, "debounce")
+
+		j.Dir, err = filepath.Abs(j.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute path of %q: %w", j.Dir, err)
+		}
+	}
+
+	return jobs, nil
+}
+
+// regenerateChanges regenerates job after changes of files, only affected interfaces are regenerated
+// when only interfaces are changed in source file. Returns code of source, which is compared on next changes.
+func regenerateChanges(ctx *cli.Context, j *job, changedFiles map[string]bool, old *sourceDecls) *sourceDecls {
+	current := parseSourceDecls(j)
+
+	interfaces, partial := affectedInterfaces(j, changedFiles, old, current)
+
+	switch {
+	case !partial:
+		regenerateJob(ctx, j, nil)
+	case len(interfaces) > 0:
+		regenerateJob(ctx, j, interfaces)
+	default:
+		slog.InfoContext(ctx.Context, "[watch] generated interfaces aren't changed", slog.String("job", j.Title))
+	}
+
+	return current
+}
+
+// regenerateJob runs job for interfaces or for all interfaces of job when they are nil and prints summary,
+// errors are logged without stopping watching.
+func regenerateJob(ctx *cli.Context, j *job, interfaces []string) {
+	started := time.Now()
+
+	err := runJobList(ctx, []*job{j}, func(command *cmd.Command, params *cmd.Params) error {
+		// editable files are edited by hand, like in regen
+		params.KeepEditable = true
+
+		if interfaces != nil {
+			params.Interfaces = interfaces
+		}
+
+		plan, err := command.Regenerate(ctx.Context, params)
+		if err != nil {
+			return err
+		}
+
+		return printWatchSummary(stdout, j, plan, time.Since(started))
+	})
+	if err != nil {
+		slog.ErrorContext(ctx.Context, "[watch] failed to regenerate", slog.String("job", j.Title), slog.Any("err", err))
+	}
+}
+
+func printWatchSummary(w io.Writer, j *job, plan *cmd.Plan, duration time.Duration) error {
+	changes := plan.Changes()
+
+	_, err := fmt.Fprintf(
+		w,
+		"[%s] %s: %d changed, %d unchanged (%s)\n",
+		time.Now().Format(time.TimeOnly),
+		j.Title,
+		len(changes),
+		len(plan.Files)-len(changes),
+		duration.Round(time.Millisecond),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range changes {
+		_, err = fmt.Fprintf(w, "  %-6s %s (%s)\n", file.Action, file.Path, strings.Join(file.Interfaces, ", "))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// watchedFiles returns absolute paths of files, which affect generation of job:
+// .go files of source package, templates and method body template.
+// Generated files are skipped, they are written by regeneration or edited by hand when they are editable.
+func watchedFiles(j *job, generated map[string]bool) []string {
+	abs := j.absPath
+
+	files := make([]string, 0)
+
+	sources, _ := filepath.Glob(filepath.Join(filepath.Dir(abs(j.Source)), "*.go"))
+	for _, source := range sources {
+		if !strings.HasSuffix(source, "_test.go") && !generated[source] {
+			files = append(files, source)
+		}
+	}
+
+	if templates := j.Opts["templates"]; templates != "" {
+		_ = filepath.WalkDir(abs(templates), func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+	}
+
+	if methodBody := j.Opts["method-body"]; strings.HasPrefix(methodBody, "file:") {
+		files = append(files, abs(strings.TrimPrefix(methodBody, "file:")))
+	}
+
+	return files
+}
+
+// sourceDecls is code of source file of job: code of interfaces and rest of code.
+type sourceDecls struct {
+	interfaces map[string]string
+	rest       string
+}
+
+// parseSourceDecls returns code of source file of job, nil when source can't be parsed.
+func parseSourceDecls(j *job) *sourceDecls {
+	src, err := os.ReadFile(j.absPath(j.Source))
+	if err != nil {
+		return nil
+	}
+
+	interfaces, err := golang.InterfaceDecls(j.Source, src)
+	if err != nil {
+		return nil
+	}
+
+	rest := string(src)
+	for name, code := range interfaces {
+		rest = strings.Replace(rest, code, name, 1)
+	}
+
+	return &sourceDecls{
+		interfaces: interfaces,
+		rest:       rest,
+	}
+}
+
+// affectedInterfaces returns changed interfaces of job, when only they are changed in source file of job
+// and job generates types in file per interface. Returns false when whole job must be regenerated:
+// types of all interfaces are generated in common file, other files are changed,
+// code outside of interfaces is changed or interfaces are removed.
+func affectedInterfaces(j *job, changedFiles map[string]bool, old, current *sourceDecls) ([]string, bool) {
+	if !hasOpt(j.Opts, "per-type") {
+		return nil, false
+	}
+
+	if len(changedFiles) != 1 || !changedFiles[j.absPath(j.Source)] {
+		return nil, false
+	}
+
+	if old == nil || current == nil || old.rest != current.rest {
+		return nil, false
+	}
+
+	for name := range old.interfaces {
+		if _, ok := current.interfaces[name]; !ok {
+			return nil, false
+		}
+	}
+
+	filter := splitInterfaces(j.Opts["interfaces"])
+
+	interfaces := make([]string, 0)
+
+	for name, code := range current.interfaces {
+		if old.interfaces[name] == code || (len(filter) > 0 && !slices.Contains(filter, name)) {
+			continue
+		}
+
+		interfaces = append(interfaces, name)
+	}
+
+	sort.Strings(interfaces)
+
+	return interfaces, true
+}
+
+// generatedFiles returns absolute paths of files recorded in manifest of module of job.
+func generatedFiles(j *job) map[string]bool {
+	files := map[string]bool{}
+
+	goMod, err := findGoModule(j.Dir)
+	if err != nil {
+		slog.Warn("[watch] failed to find go.mod of job", slog.String("job", j.Title), slog.Any("err", err))
+
+		return files
+	}
+
+	moduleDir, err := filepath.Abs(filepath.Dir(goMod.Path))
+	if err != nil {
+		slog.Warn("[watch] failed to get module directory of job", slog.String("job", j.Title), slog.Any("err", err))
+
+		return files
+	}
+
+	manifest, err := cmd.LoadManifest(moduleDir)
+	if err != nil {
+		slog.Warn("[watch] failed to load manifest of job", slog.String("job", j.Title), slog.Any("err", err))
+
+		return files
+	}
+
+	for _, file := range manifest.Files {
+		files[manifest.AbsPath(file)] = true
+	}
+
+	return files
+}
+
+func takeSnapshot(files []string) snapshot {
+	snap := snapshot{}
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			snap[file] = "missing"
+
+			continue
+		}
+
+		snap[file] = fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+	}
+
+	return snap
+}
+
+// changed returns files, which state differs from state in other snapshot.
+func (s snapshot) changed(other snapshot) []string {
+	files := make([]string, 0)
+
+	for file, state := range s {
+		if other[file] != state {
+			files = append(files, file)
+		}
+	}
+
+	for file := range other {
+		if _, ok := s[file]; !ok {
+			files = append(files, file)
+		}
+	}
+
+	sort.Strings(files)
+
+	return files
+}
+
+func (s snapshot) equal(other snapshot) bool {
+	if len(s) != len(other) {
+		return false
+	}
+
+	for file, state := range s {
+		if other[file] != state {
+			return false
+		}
+	}
+
+	return true
+}
+
+// configFiles returns config of jobs, config isn't used when source argument is set.
+func configFiles(ctx *cli.Context) []string {
+	if ctx.GetArg("source") != "" {
+		return nil
+	}
+
+	return []string{configPath(ctx)}
+}
+
+func jobIndexes(jobs []*job) []int {
+	indexes := make([]int, len(jobs))
+	for i := range jobs {
+		indexes[i] = i
+	}
+
+	return indexes
+}
+
+func durationOpt(ctx *cli.Context, name string, def time.Duration) (time.Duration, error) {
+	value, ok := ctx.Opts[name]
+	if !ok || value == "" {
+		return def, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s: %w", name, err)
+	}
+
+	return duration, nil
+}
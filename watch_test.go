@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artarts36/gostub/internal/cmd"
+)
+
+func TestWatchOptsDontContainOptsWithoutWriting(t *testing.T) {
+	names := map[string]bool{}
+	for _, opt := range watchCmd().app().Opts {
+		names[opt.Name] = true
+	}
+
+	for _, name := range []string{"dry-run", "diff", "check"} {
+		if names[name] {
+			t.Errorf("watch accepts --%s, which doesn't write files", name)
+		}
+	}
+
+	for _, name := range []string{"interval", "debounce", "out", "kind", optConfig} {
+		if !names[name] {
+			t.Errorf("watch doesn't accept --%s", name)
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestPrintWatchSummary(t *testing.T) {
+	plan := &cmd.Plan{
+		Files: []*cmd.PlannedFile{
+			{Path: "stubs/users.go", Action: cmd.FileActionChange, Interfaces: []string{"Users"}},
+			{Path: "stubs/notifier.go", Action: cmd.FileActionKeep, Interfaces: []string{"Notifier"}},
+		},
+	}
+
+	out := &bytes.Buffer{}
+
+	err := printWatchSummary(out, &job{Title: "stubs"}, plan, 1500*time.Microsecond)
+	if err != nil {
+		t.Fatalf("printWatchSummary() error = %v", err)
+	}
+
+	// time of regeneration is printed first
+	_, summary, _ := strings.Cut(out.String(), "] ")
+
+	want := "stubs: 1 changed, 1 unchanged (2ms)\n  change stubs/users.go (Users)\n"
+	if summary != want {
+		t.Errorf("printWatchSummary() printed %q, want %q", summary, want)
+	}
+
+	err = printWatchSummary(failingWriter{}, &job{Title: "stubs"}, plan, 0)
+	if err == nil {
+		t.Error("printWatchSummary() must return error of writer")
+	}
+}
+
+const testContracts = `package contracts
+
+import "context"
+
+type Users interface {
+	Get(ctx context.Context, id int) (string, error)
+}
+`
+
+func TestWatchedFiles(t *testing.T) {
+	manifest := newTestModule(t, map[string]*cmd.ManifestFile{
+		"contracts/users_get.go": {Editable: true},
+	}, map[string]string{
+		"contracts/contracts.go":      testContracts,
+		"contracts/contracts_test.go": "package contracts\n",
+		"contracts/users_get.go":      "package contracts\n",
+		"tpl/stub.tpl":                "",
+		"tpl/nested/method.tpl":       "",
+		"body.tpl":                    "",
+	})
+
+	j := &job{
+		Dir:    manifest.Dir,
+		Source: "./contracts/contracts.go",
+		Opts: map[string]string{
+			"templates":   "./tpl",
+			"method-body": "file:./body.tpl",
+		},
+	}
+
+	files := watchedFiles(j, generatedFiles(j))
+	sort.Strings(files)
+
+	want := []string{
+		filepath.Join(manifest.Dir, "body.tpl"),
+		filepath.Join(manifest.Dir, "contracts", "contracts.go"),
+		filepath.Join(manifest.Dir, "tpl", "nested", "method.tpl"),
+		filepath.Join(manifest.Dir, "tpl", "stub.tpl"),
+	}
+
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("watchedFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestRegenerateJobKeepsEditableFiles(t *testing.T) {
+	manifest := newTestModule(t, nil, map[string]string{
+		"contracts/contracts.go": testContracts,
+	})
+
+	captureStdout(t)
+
+	ctx := newTestContext(nil)
+	j := &job{
+		Title:  "contracts",
+		Dir:    manifest.Dir,
+		Source: "./contracts/contracts.go",
+		Opts: map[string]string{
+			"out":                 "./stubs",
+			"per-method":          "",
+			"editable-per-method": "",
+		},
+	}
+
+	regenerateJob(ctx, j, nil)
+
+	edited := filepath.Join(manifest.Dir, "stubs", "users_get_stub.go")
+	writeTestFile(t, edited, "package stubs\n\n// edited\n")
+
+	regenerateJob(ctx, j, nil)
+
+	content, err := os.ReadFile(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "package stubs\n\n// edited\n" {
+		t.Errorf("editable file is overwritten:\n%s", content)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	c := filepath.Join(dir, "c.go")
+
+	writeTestFile(t, a, "package a\n")
+	writeTestFile(t, b, "package a\n")
+
+	old := takeSnapshot([]string{a, b, c})
+	if old[c] != "missing" {
+		t.Errorf("state of missing file = %q, want missing", old[c])
+	}
+
+	if !old.equal(takeSnapshot([]string{a, b, c})) {
+		t.Error("snapshots of unchanged files must be equal")
+	}
+
+	writeTestFile(t, b, "package a\n\n// changed\n")
+	writeTestFile(t, c, "package a\n")
+
+	current := takeSnapshot([]string{a, b, c})
+	if current.equal(old) {
+		t.Error("snapshots of changed files must not be equal")
+	}
+
+	if changed := current.changed(old); !reflect.DeepEqual(changed, []string{b, c}) {
+		t.Errorf("changed() = %v, want %v", changed, []string{b, c})
+	}
+
+	removed := takeSnapshot([]string{a})
+	if changed := removed.changed(current); !reflect.DeepEqual(changed, []string{b, c}) {
+		t.Errorf("changed() of removed files = %v, want %v", changed, []string{b, c})
+	}
+}
+
+func TestDurationOpt(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    map[string]string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", opts: map[string]string{}, want: time.Second},
+		{name: "empty", opts: map[string]string{"interval": ""}, want: time.Second},
+		{name: "set", opts: map[string]string{"interval": "150ms"}, want: 150 * time.Millisecond},
+		{name: "invalid", opts: map[string]string{"interval": "fast"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := durationOpt(newTestContext(c.opts), "interval", time.Second)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("durationOpt() error = %v, want error %v", err, c.wantErr)
+			}
+
+			if got != c.want {
+				t.Errorf("durationOpt() = %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestAffectedInterfaces(t *testing.T) {
+	const source = "/m/contracts/contracts.go"
+
+	decls := func(rest string, interfaces ...string) *sourceDecls {
+		d := &sourceDecls{interfaces: map[string]string{}, rest: rest}
+		for _, name := range interfaces {
+			d.interfaces[name] = name + " interface{}"
+		}
+
+		return d
+	}
+
+	old := decls("package contracts", "Users", "Notifier")
+	changedUsers := decls("package contracts", "Users", "Notifier")
+	changedUsers.interfaces["Users"] = "Users interface{ Get() }"
+
+	cases := []struct {
+		name        string
+		opts        map[string]string
+		files       []string
+		current     *sourceDecls
+		want        []string
+		wantPartial bool
+	}{
+		{
+			name:        "changed interface",
+			opts:        map[string]string{"per-type": ""},
+			files:       []string{source},
+			current:     changedUsers,
+			want:        []string{"Users"},
+			wantPartial: true,
+		},
+		{
+			name:        "added interface",
+			opts:        map[string]string{"per-type": "", "per-method": ""},
+			files:       []string{source},
+			current:     decls("package contracts", "Users", "Notifier", "Cache"),
+			want:        []string{"Cache"},
+			wantPartial: true,
+		},
+		{
+			name:        "changed interface isn't generated by job",
+			opts:        map[string]string{"per-type": "", "interfaces": "Notifier"},
+			files:       []string{source},
+			current:     changedUsers,
+			want:        []string{},
+			wantPartial: true,
+		},
+		{
+			name:    "types in common file of per method files",
+			opts:    map[string]string{"per-method": ""},
+			files:   []string{source},
+			current: changedUsers,
+		},
+		{
+			name:    "single file",
+			opts:    map[string]string{},
+			files:   []string{source},
+			current: changedUsers,
+		},
+		{
+			name:    "other file is changed",
+			opts:    map[string]string{"per-type": ""},
+			files:   []string{source, "/m/contracts/user.go"},
+			current: changedUsers,
+		},
+		{
+			name:    "code outside of interfaces is changed",
+			opts:    map[string]string{"per-type": ""},
+			files:   []string{source},
+			current: decls("package contracts\n\ntype ID int", "Users", "Notifier"),
+		},
+		{
+			name:    "removed interface",
+			opts:    map[string]string{"per-type": ""},
+			files:   []string{source},
+			current: decls("package contracts", "Users"),
+		},
+		{
+			name:  "source isn't parsed",
+			opts:  map[string]string{"per-type": ""},
+			files: []string{source},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files := map[string]bool{}
+			for _, file := range c.files {
+				files[file] = true
+			}
+
+			j := &job{Dir: "/m", Source: "./contracts/contracts.go", Opts: c.opts}
+
+			got, partial := affectedInterfaces(j, files, old, c.current)
+			if partial != c.wantPartial || !reflect.DeepEqual(got, c.want) {
+				t.Errorf("affectedInterfaces() = (%v, %v), want (%v, %v)", got, partial, c.want, c.wantPartial)
+			}
+		})
+	}
+}
+
+func TestRegenerateJobOfInterfaces(t *testing.T) {
+	manifest := newTestModule(t, nil, map[string]string{
+		"contracts/contracts.go": testContracts + "\ntype Notifier interface {\n\tNotify(message string) error\n}\n",
+	})
+
+	out := captureStdout(t)
+
+	ctx := newTestContext(nil)
+	j := &job{
+		Title:  "contracts",
+		Dir:    manifest.Dir,
+		Source: "./contracts/contracts.go",
+		Opts:   map[string]string{"out": "./stubs", "per-type": ""},
+	}
+
+	regenerateJob(ctx, j, nil)
+
+	out.Reset()
+
+	writeTestFile(
+		t,
+		filepath.Join(manifest.Dir, "contracts", "contracts.go"),
+		testContracts+"\ntype Notifier interface {\n\tNotify(message string) error\n\tClose() error\n}\n",
+	)
+
+	regenerateJob(ctx, j, []string{"Notifier"})
+
+	if !strings.Contains(out.String(), ": 1 changed, 0 unchanged") || !strings.Contains(out.String(), "(Notifier)") {
+		t.Errorf("only stub of Notifier must be regenerated, got:\n%s", out.String())
+	}
+
+	notifier, err := os.ReadFile(filepath.Join(manifest.Dir, "stubs", "notifier_stub.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(notifier), "Close() error") {
+		t.Errorf("stub of Notifier isn't regenerated:\n%s", notifier)
+	}
+
+	recorded, err := cmd.LoadManifest(manifest.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range recorded.Files {
+		if file.Stale {
+			t.Errorf("file %s of not regenerated interface is stale", file.Path)
+		}
+	}
+}
+
+func TestPendingChanges(t *testing.T) {
+	start := time.Now()
+	changes := newPendingChanges(300 * time.Millisecond)
+
+	if indexes, _ := changes.release(start); indexes != nil {
+		t.Errorf("release() without changes = %v, want nothing", indexes)
+	}
+
+	changes.add(2, []string{"/m/a.go"}, start)
+	changes.add(0, []string{"/m/tpl/stub.tpl"}, start.Add(200*time.Millisecond))
+
+	// debounce is counted from last change
+	if indexes, _ := changes.release(start.Add(400 * time.Millisecond)); indexes != nil {
+		t.Errorf("release() before debounce = %v, want nothing", indexes)
+	}
+
+	changes.add(2, []string{"/m/b.go"}, start.Add(450*time.Millisecond))
+
+	indexes, files := changes.release(start.Add(750 * time.Millisecond))
+
+	if !reflect.DeepEqual(indexes, []int{0, 2}) {
+		t.Errorf("release() = %v, want [0 2]", indexes)
+	}
+
+	want := map[int]map[string]bool{
+		0: {"/m/tpl/stub.tpl": true},
+		2: {"/m/a.go": true, "/m/b.go": true},
+	}
+
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("release() files = %v, want %v", files, want)
+	}
+
+	if indexes, _ = changes.release(start.Add(time.Second)); indexes != nil {
+		t.Errorf("release() of released changes = %v, want nothing", indexes)
+	}
+}
+
+func TestRegenerateChangesOfPerMethodJob(t *testing.T) {
+	contracts := testContracts + "\ntype Notifier interface {\n\tNotify(message string) error\n}\n"
+
+	manifest := newTestModule(t, nil, map[string]string{
+		"contracts/contracts.go": contracts,
+	})
+
+	captureStdout(t)
+
+	ctx := newTestContext(nil)
+	j := &job{
+		Title:  "contracts",
+		Dir:    manifest.Dir,
+		Source: "./contracts/contracts.go",
+		Opts:   map[string]string{"out": "./stubs", "per-method": ""},
+	}
+
+	source := regenerateChanges(ctx, j, nil, nil)
+
+	writeTestFile(
+		t,
+		filepath.Join(manifest.Dir, "contracts", "contracts.go"),
+		strings.Replace(contracts, "Notify(message string) error", "Notify(message string) error\n\tClose() error", 1),
+	)
+
+	regenerateChanges(ctx, j, map[string]bool{j.absPath(j.Source): true}, source)
+
+	// types of all interfaces are generated in common file
+	common, err := os.ReadFile(filepath.Join(manifest.Dir, "stubs", "stubs.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, typ := range []string{"type StubUsers struct", "type StubNotifier struct", "func NewStubUsers()"} {
+		if !strings.Contains(string(common), typ) {
+			t.Errorf("common file doesn't contain %s:\n%s", typ, common)
+		}
+	}
+
+	if _, err = os.Stat(filepath.Join(manifest.Dir, "stubs", "notifier_close_stub.go")); err != nil {
+		t.Errorf("file of added method isn't generated: %v", err)
+	}
+}